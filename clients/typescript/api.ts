@@ -0,0 +1,517 @@
+// Code generated by tsclient from pkg/openapi/openapi.yaml. DO NOT EDIT.
+// TX QR Tool API 1.0.0
+
+export interface AttributionReportResponse {
+  by_campaign: CountEntry[];
+  by_medium: CountEntry[];
+  by_referral_code: CountEntry[];
+  by_source: CountEntry[];
+  total: number;
+}
+
+export interface AuditLogListResponse {
+  audit_logs: AuditLogResponse[];
+  limit: number;
+  offset: number;
+  total: number;
+}
+
+export interface AuditLogResponse {
+  action: string;
+  actor: string;
+  changes: Record<string, FieldChange>;
+  created_on: string;
+  entity_id: string;
+  entity_type: string;
+  id: number;
+  /** Client network (/24 for IPv4, /48 for IPv6), not the full address */
+  ip_address: string;
+  metadata: Record<string, unknown>;
+  request_id: string;
+}
+
+export interface BatchItemResult {
+  code?: string;
+  errors?: ValidationError[];
+  index: number;
+  message?: string;
+  registration?: RegistrationResponse;
+  status: "CREATED" | "FAILED";
+}
+
+export interface BatchRegistrationRequest {
+  group_name: string;
+  mode?: "atomic" | "partial";
+  primary_contact_index?: number;
+  registrations: CreateRegistrationRequest[];
+}
+
+export interface BatchRegistrationResponse {
+  failed: number;
+  group_id: number | null;
+  group_name: string;
+  mode: "atomic" | "partial";
+  primary_registration_id: number | null;
+  results: BatchItemResult[];
+  succeeded: number;
+  total: number;
+}
+
+export interface CachedResponseSummary {
+  created_on: string;
+  expires_at: string;
+  idempotency_key: string;
+  method: string;
+  path: string;
+}
+
+export interface CountEntry {
+  count: number;
+  value: string;
+}
+
+export interface CreateInviteCodeRequest {
+  /** Letters, digits, '-' and '_'; stored upper-case */
+  code: string;
+  /** Must be in the future */
+  expires_at?: string;
+  label?: string;
+  max_uses?: number | null;
+}
+
+export interface CreateReferralCodeRequest {
+  /** Letters, digits, '-' and '_'; stored upper-case */
+  code: string;
+  description?: string;
+  owner: string;
+}
+
+export interface CreateRegistrationRequest {
+  designation?: string;
+  email: string;
+  food_pref: string;
+  full_name: string;
+  invite_code?: string;
+  mkt_source?: string;
+  org_name?: string;
+  phone: string;
+  referral_code?: string;
+  referrer?: string;
+  t_shirt: "S" | "M" | "L" | "XL" | "XXL" | "XXXL";
+  utm_campaign?: string;
+  utm_content?: string;
+  utm_medium?: string;
+  utm_source?: string;
+  utm_term?: string;
+}
+
+export interface DataSubjectErasureResponse {
+  erased_on: string;
+  erased_registration_ids: number[];
+  purged_cached_responses: number;
+}
+
+export interface DataSubjectExportResponse {
+  audit_logs: AuditLogResponse[];
+  cached_responses: CachedResponseSummary[];
+  generated_on: string;
+  groups: RegistrationGroupResponse[];
+  registrations: RegistrationResponse[];
+}
+
+/** At least one of email or phone is required */
+export interface DataSubjectRequest {
+  email?: string;
+  phone?: string;
+}
+
+export interface ErrorCode {
+  code: string;
+  description: string;
+  http_status: number;
+}
+
+export interface ErrorResponse {
+  code: string;
+  errors?: ValidationError[];
+  message: string;
+  request_id: string;
+  status: "ERROR";
+}
+
+export interface FieldChange {
+  new?: unknown;
+  old?: unknown;
+}
+
+export interface HealthCheckResult {
+  detail?: string;
+  duration_ms: number;
+  status: "pass" | "fail";
+}
+
+export interface HealthResponse {
+  checks?: Record<string, HealthCheckResult>;
+  status: "pass" | "fail";
+}
+
+export type ImportReportEnvelope = SuccessResponse & {
+  data?: ImportReportResponse;
+};
+
+export interface ImportReportResponse {
+  column_mapping: Record<string, string>;
+  dry_run: boolean;
+  format: "csv" | "xlsx";
+  imported: number;
+  invalid_rows: number;
+  /** IDs of the imported registrations; empty for a dry run */
+  registration_ids: number[];
+  row_errors: ImportRowError[];
+  total_rows: number;
+  valid_rows: number;
+}
+
+export interface ImportRequest {
+  /** A .csv or .xlsx file of at most 10 MiB */
+  file: Blob;
+  /** JSON object mapping file headers to registration fields */
+  mapping?: string;
+}
+
+export interface ImportRowError {
+  code: string;
+  errors: ValidationError[];
+  row: number;
+}
+
+export interface InviteCodeListResponse {
+  invite_codes: InviteCodeResponse[];
+  total: number;
+}
+
+export interface InviteCodeResponse {
+  active: boolean;
+  code: string;
+  created_on: string;
+  expires_at: string | null;
+  id: number;
+  label: string;
+  max_uses: number | null;
+  remaining: number | null;
+  used_count: number;
+}
+
+export interface LegacyHealthResponse {
+  status: "healthy" | "draining";
+}
+
+export interface PeriodCountEntry {
+  count: number;
+  period: string;
+}
+
+export interface ReferralCodeListResponse {
+  referral_codes: ReferralCodeResponse[];
+  total: number;
+}
+
+export interface ReferralCodeResponse {
+  active: boolean;
+  code: string;
+  created_on: string;
+  description: string;
+  id: number;
+  owner: string;
+}
+
+export interface RegistrationGroupResponse {
+  created_on: string;
+  id: number;
+  name: string;
+  primary_registration_id: number | null;
+}
+
+export interface RegistrationResponse {
+  created_on: string;
+  designation: string;
+  email: string;
+  food_pref: string;
+  full_name: string;
+  id: number;
+  invite_code?: string;
+  mkt_source: string;
+  org_name: string;
+  phone: string;
+  referral_code?: string;
+  referrer?: string;
+  t_shirt: string;
+  utm_campaign?: string;
+  utm_content?: string;
+  utm_medium?: string;
+  utm_source?: string;
+  utm_term?: string;
+}
+
+export interface RegistrationStatsResponse {
+  by_food_pref: CountEntry[];
+  by_mkt_source: CountEntry[];
+  by_org_name: CountEntry[];
+  by_t_shirt: CountEntry[];
+  generated_on: string;
+  per_day: PeriodCountEntry[];
+  per_hour: PeriodCountEntry[];
+  total: number;
+}
+
+/**
+ * The deployment's single retention policy, set by RETENTION_DAYS,
+ * RETENTION_ACTION and RETENTION_EVENT_END_DATE. Registrations are not
+ * tied to events, so one policy applies to every registration; run a
+ * deployment per event to give events different policies.
+ */
+export interface RetentionPolicyResponse {
+  action: string;
+  enabled: boolean;
+  event_end_date: string | null;
+  retention_days: number;
+}
+
+export interface RetentionPreviewResponse {
+  cutoff: string | null;
+  due: boolean;
+  newest_created_on: string | null;
+  oldest_created_on: string | null;
+  policy: RetentionPolicyResponse;
+  registration_ids: number[];
+  registrations_affected: number;
+}
+
+export interface SuccessResponse {
+  message: string;
+  request_id: string;
+  status: "SUCCESS";
+}
+
+export interface ValidationError {
+  field: string;
+  message: string;
+}
+
+export interface ApiClientOptions {
+  /** Base URL of the API, without a trailing slash. */
+  baseUrl: string;
+  /** Sent as X-Api-Key on every request. */
+  apiKey?: string;
+  /** Defaults to the global fetch. */
+  fetch?: typeof fetch;
+}
+
+/** Thrown for every non-2xx response. */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly code: string;
+  readonly requestId: string | undefined;
+  readonly errors: ValidationError[];
+
+  constructor(status: number, body: Partial<ErrorResponse> | undefined) {
+    super(body?.message ?? `Request failed with status ${status}`);
+    this.name = "ApiError";
+    this.status = status;
+    this.code = body?.code ?? "UNKNOWN_ERROR";
+    this.requestId = body?.request_id;
+    this.errors = body?.errors ?? [];
+  }
+}
+
+type QueryValue = string | number | boolean | undefined;
+
+function toFormData(body: object): FormData {
+  const form = new FormData();
+  for (const [key, value] of Object.entries(body)) {
+    if (value === undefined || value === null) continue;
+    form.append(key, value instanceof Blob ? value : String(value));
+  }
+  return form;
+}
+
+export class ApiClient {
+  private readonly options: ApiClientOptions;
+
+  constructor(options: ApiClientOptions) {
+    this.options = options;
+  }
+
+  private url(path: string, query: Record<string, QueryValue>): string {
+    const search = new URLSearchParams();
+    for (const [key, value] of Object.entries(query)) {
+      if (value !== undefined) search.append(key, String(value));
+    }
+    const qs = search.toString();
+    return this.options.baseUrl + path + (qs ? "?" + qs : "");
+  }
+
+  private async request(
+    method: string,
+    path: string,
+    query: Record<string, QueryValue>,
+    headers: Record<string, string | undefined>,
+    body: unknown,
+    responseKind: "json" | "blob" | "none",
+  ): Promise<unknown> {
+    const init: RequestInit = { method, headers: {} };
+    const requestHeaders = init.headers as Record<string, string>;
+    if (this.options.apiKey) requestHeaders["X-Api-Key"] = this.options.apiKey;
+    for (const [key, value] of Object.entries(headers)) {
+      if (value !== undefined) requestHeaders[key] = value;
+    }
+    if (body instanceof FormData) {
+      init.body = body;
+    } else if (body !== undefined) {
+      requestHeaders["Content-Type"] = "application/json";
+      init.body = JSON.stringify(body);
+    }
+
+    const doFetch = this.options.fetch ?? fetch;
+    const response = await doFetch(this.url(path, query), init);
+    if (!response.ok) {
+      const errorBody = await response.json().catch(() => undefined);
+      throw new ApiError(response.status, errorBody);
+    }
+    if (responseKind === "blob") return response.blob();
+    if (responseKind === "none") return undefined;
+    return response.json();
+  }
+
+  /** Registrations by UTM parameter and referral code */
+  async getAttributionReport(): Promise<AttributionReportResponse> {
+    const result = await this.request("GET", "/attribution", {}, {}, undefined, "json");
+    return (result as { data: AttributionReportResponse }).data;
+  }
+
+  /**
+   * Search the audit log
+   *
+   * Each search is itself recorded as an `audit_log.read` entry, and
+   * refused if that entry cannot be written.
+   */
+  async listAuditLogs(params: { actor?: string; action?: string; entity_type?: string; entity_id?: string; request_id?: string; from?: string; to?: string; limit?: number; offset?: number } = {}): Promise<AuditLogListResponse> {
+    const result = await this.request("GET", "/audit-logs", { actor: params["actor"], action: params["action"], entity_type: params["entity_type"], entity_id: params["entity_id"], request_id: params["request_id"], from: params["from"], to: params["to"], limit: params["limit"], offset: params["offset"] }, {}, undefined, "json");
+    return (result as { data: AuditLogListResponse }).data;
+  }
+
+  /** Erase a person's personal data */
+  async eraseDataSubject(body: DataSubjectRequest): Promise<DataSubjectErasureResponse> {
+    const result = await this.request("POST", "/data-subject/erase", {}, {}, body, "json");
+    return (result as { data: DataSubjectErasureResponse }).data;
+  }
+
+  /** Export everything held about a person */
+  async exportDataSubject(body: DataSubjectRequest): Promise<DataSubjectExportResponse> {
+    const result = await this.request("POST", "/data-subject/export", {}, {}, body, "json");
+    return (result as { data: DataSubjectExportResponse }).data;
+  }
+
+  /** Export every registration as CSV */
+  async downloadCsv(): Promise<Blob> {
+    const result = await this.request("GET", "/download-csv", {}, {}, undefined, "blob");
+    return result as Blob;
+  }
+
+  /** Catalogue of error codes */
+  async listErrorCodes(): Promise<ErrorCode[]> {
+    const result = await this.request("GET", "/error-codes", {}, {}, undefined, "json");
+    return (result as { data: ErrorCode[] }).data;
+  }
+
+  /** Legacy health check; fails only while draining */
+  async health(): Promise<LegacyHealthResponse> {
+    const result = await this.request("GET", "/health", {}, {}, undefined, "json");
+    return result as LegacyHealthResponse;
+  }
+
+  /** Import registrations from a CSV or XLSX file */
+  async importRegistrations(body: ImportRequest, params: { dry_run?: boolean; skip_invalid?: boolean } = {}): Promise<ImportReportResponse> {
+    const result = await this.request("POST", "/import", { dry_run: params["dry_run"], skip_invalid: params["skip_invalid"] }, {}, toFormData(body), "json");
+    return (result as { data: ImportReportResponse }).data;
+  }
+
+  /** List invite codes */
+  async listInviteCodes(): Promise<InviteCodeListResponse> {
+    const result = await this.request("GET", "/invite-codes", {}, {}, undefined, "json");
+    return (result as { data: InviteCodeListResponse }).data;
+  }
+
+  /** Create an invite code */
+  async createInviteCode(body: CreateInviteCodeRequest): Promise<InviteCodeResponse> {
+    const result = await this.request("POST", "/invite-codes", {}, {}, body, "json");
+    return (result as { data: InviteCodeResponse }).data;
+  }
+
+  /**
+   * Server-sent events for new registrations
+   *
+   * Emits `connected` once and `heartbeat` periodically. Each insert
+   * emits `registration_created` (`id`, `created_on`) for one row or
+   * `registrations_created` (`count`, `first_id`, `last_id`) for a
+   * batch or import. Events carry no totals; call /stats for those.
+   * EventSource cannot send headers, so the key may be passed as
+   * `api_key` instead.
+   */
+  streamLiveUrl(): string {
+    return this.url("/live", this.options.apiKey ? { api_key: this.options.apiKey } : {});
+  }
+
+  /** Liveness probe */
+  async livez(): Promise<HealthResponse> {
+    const result = await this.request("GET", "/livez", {}, {}, undefined, "json");
+    return result as HealthResponse;
+  }
+
+  /** Readiness probe with dependency checks */
+  async readyz(): Promise<HealthResponse> {
+    const result = await this.request("GET", "/readyz", {}, {}, undefined, "json");
+    return result as HealthResponse;
+  }
+
+  /** List referral codes */
+  async listReferralCodes(): Promise<ReferralCodeListResponse> {
+    const result = await this.request("GET", "/referral-codes", {}, {}, undefined, "json");
+    return (result as { data: ReferralCodeListResponse }).data;
+  }
+
+  /** Create a referral code */
+  async createReferralCode(body: CreateReferralCodeRequest): Promise<ReferralCodeResponse> {
+    const result = await this.request("POST", "/referral-codes", {}, {}, body, "json");
+    return (result as { data: ReferralCodeResponse }).data;
+  }
+
+  /** Create a registration */
+  async register(body: CreateRegistrationRequest, params: { "Idempotency-Key"?: string } = {}): Promise<RegistrationResponse> {
+    const result = await this.request("POST", "/register", {}, { "Idempotency-Key": params["Idempotency-Key"] }, body, "json");
+    return (result as { data: RegistrationResponse }).data;
+  }
+
+  /** Register a group in one request */
+  async registerBatch(body: BatchRegistrationRequest, params: { "Idempotency-Key"?: string } = {}): Promise<BatchRegistrationResponse> {
+    const result = await this.request("POST", "/register/batch", {}, { "Idempotency-Key": params["Idempotency-Key"] }, body, "json");
+    return (result as { data: BatchRegistrationResponse }).data;
+  }
+
+  /**
+   * Show what the next retention purge would remove
+   *
+   * Each preview is recorded as a `retention.preview` entry, and
+   * refused if that entry cannot be written.
+   */
+  async previewRetention(): Promise<RetentionPreviewResponse> {
+    const result = await this.request("GET", "/retention/preview", {}, {}, undefined, "json");
+    return (result as { data: RetentionPreviewResponse }).data;
+  }
+
+  /** Registration statistics */
+  async getStats(): Promise<RegistrationStatsResponse> {
+    const result = await this.request("GET", "/stats", {}, {}, undefined, "json");
+    return (result as { data: RegistrationStatsResponse }).data;
+  }
+}
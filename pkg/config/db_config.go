@@ -5,6 +5,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/tracing"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog/log"
 )
@@ -26,6 +27,8 @@ func GetDBConnection() *pgxpool.Pool {
 			log.Fatal().Err(err).Msg("Unable to parse database URL")
 		}
 
+		poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
+
 		dbInstance, err = pgxpool.NewWithConfig(context.Background(), poolConfig)
 		if err != nil {
 			log.Fatal().Err(err).Msg("Unable to connect to database")
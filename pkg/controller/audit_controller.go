@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"net"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/service"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/rs/zerolog"
+)
+
+type AuditController struct {
+	service service.AuditService
+}
+
+func NewAuditController(service service.AuditService) *AuditController {
+	return &AuditController{service: service}
+}
+
+func (ac *AuditController) ListAuditLogs(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	var query dto.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("INVALID_QUERY", "Invalid query parameters", err), requestID)
+		return
+	}
+
+	response, err := ac.service.ListAuditLogs(c.Request.Context(), &query)
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	// Reading the log is itself audited, like any other export, and
+	// refused if that entry cannot be written.
+	entry := newAuditEntry(c, requestID, models.AuditActionAuditLogRead, "audit_log", "")
+	entry.Metadata = map[string]interface{}{
+		"filters":  auditQueryFilters(&query),
+		"returned": len(response.AuditLogs),
+		"total":    response.Total,
+	}
+	if err := ac.service.Record(c.Request.Context(), entry); err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	utils.SendOKResponse(c, "Audit logs retrieved successfully", requestID, response)
+}
+
+// auditQueryFilters lists the filters a read of the audit log used.
+func auditQueryFilters(query *dto.AuditLogQuery) map[string]interface{} {
+	filters := map[string]interface{}{
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	}
+	for name, value := range map[string]string{
+		"actor":       query.Actor,
+		"action":      query.Action,
+		"entity_type": query.EntityType,
+		"entity_id":   query.EntityID,
+		"request_id":  query.RequestID,
+		"from":        query.From,
+		"to":          query.To,
+	} {
+		if value != "" {
+			filters[name] = value
+		}
+	}
+	return filters
+}
+
+func newAuditEntry(c *gin.Context, requestID, action, entityType, entityID string) *models.AuditLog {
+	return &models.AuditLog{
+		Actor:      utils.GetActor(c),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		RequestID:  requestID,
+		IPAddress:  coarseIP(c.ClientIP()),
+		Changes:    map[string]models.FieldChange{},
+		Metadata:   map[string]interface{}{},
+	}
+}
+
+// recordMutation writes the audit entry for a change that has already been
+// committed. A failure cannot undo the change, so it is logged instead of
+// being returned to the client. The write is not tied to the client
+// staying connected.
+func recordMutation(ctx context.Context, audit service.AuditService, entry *models.AuditLog) {
+	if err := audit.Record(context.WithoutCancel(ctx), entry); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("action", entry.Action).
+			Str("entity_id", entry.EntityID).
+			Msg("Failed to write audit log")
+	}
+}
+
+// coarseIP reduces an address to its network (/24 for IPv4, /48 for
+// IPv6) so audit entries show where a request came from without
+// identifying the person who sent it.
+func coarseIP(ip string) string {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return ""
+	}
+	if v4 := addr.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: addr.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String()
+}
+
+func created(value interface{}) models.FieldChange {
+	return models.FieldChange{Old: nil, New: value}
+}
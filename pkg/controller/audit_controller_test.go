@@ -0,0 +1,18 @@
+package controller
+
+import "testing"
+
+func TestCoarseIP(t *testing.T) {
+	tests := map[string]string{
+		"203.0.113.77":        "203.0.113.0/24",
+		"::ffff:198.51.100.9": "198.51.100.0/24",
+		"2001:db8:abcd:12::1": "2001:db8:abcd::/48",
+		"":                    "",
+		"not-an-ip":           "",
+	}
+	for ip, want := range tests {
+		if got := coarseIP(ip); got != want {
+			t.Errorf("coarseIP(%q) = %q, want %q", ip, got, want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/metrics"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/service"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type DataSubjectController struct {
+	service service.DataSubjectService
+	audit   service.AuditService
+}
+
+func NewDataSubjectController(service service.DataSubjectService, audit service.AuditService) *DataSubjectController {
+	return &DataSubjectController{service: service, audit: audit}
+}
+
+func (dc *DataSubjectController) Export(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	var req dto.DataSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("INVALID_JSON", "Invalid JSON format", err), requestID)
+		return
+	}
+
+	observeExport := metrics.TimeExport("data_subject")
+	response, err := dc.service.ExportData(c.Request.Context(), &req)
+	observeExport()
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	registrationIDs := make([]int, 0, len(response.Registrations))
+	for _, reg := range response.Registrations {
+		registrationIDs = append(registrationIDs, reg.ID)
+	}
+
+	entry := newAuditEntry(c, requestID, models.AuditActionDataSubjectExport, "data_subject", "")
+	entry.Metadata = map[string]interface{}{"registration_ids": registrationIDs}
+	if err := dc.audit.Record(c.Request.Context(), entry); err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	utils.SendOKResponse(c, "Personal data exported successfully", requestID, response)
+}
+
+func (dc *DataSubjectController) Erase(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	var req dto.DataSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("INVALID_JSON", "Invalid JSON format", err), requestID)
+		return
+	}
+
+	response, err := dc.service.EraseData(c.Request.Context(), &req)
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	entry := newAuditEntry(c, requestID, models.AuditActionDataSubjectErase, "data_subject", "")
+	entry.Metadata = map[string]interface{}{
+		"registration_ids":        response.ErasedRegistrationIDs,
+		"purged_cached_responses": response.PurgedCachedResponses,
+	}
+	recordMutation(c.Request.Context(), dc.audit, entry)
+
+	utils.SendOKResponse(c, "Personal data erased successfully", requestID, response)
+}
@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type ErrorCodeController struct{}
+
+func NewErrorCodeController() *ErrorCodeController {
+	return &ErrorCodeController{}
+}
+
+func (ec *ErrorCodeController) ListErrorCodes(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+	utils.SendOKResponse(c, "Error codes retrieved successfully", requestID, utils.ErrorCatalogue)
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/service"
+)
+
+type HealthController struct {
+	service service.HealthService
+}
+
+func NewHealthController(service service.HealthService) *HealthController {
+	return &HealthController{service: service}
+}
+
+// Health is kept for existing load balancer checks. It fails only while
+// the server is draining.
+func (hc *HealthController) Health(c *gin.Context) {
+	if hc.service.Draining() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "draining",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": "healthy",
+	})
+}
+
+func (hc *HealthController) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, hc.service.Liveness())
+}
+
+func (hc *HealthController) Readyz(c *gin.Context) {
+	response, ready := hc.service.Readiness(c.Request.Context())
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
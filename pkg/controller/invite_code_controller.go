@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/service"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type InviteCodeController struct {
+	service service.InviteCodeService
+	audit   service.AuditService
+}
+
+func NewInviteCodeController(service service.InviteCodeService, audit service.AuditService) *InviteCodeController {
+	return &InviteCodeController{service: service, audit: audit}
+}
+
+func (ic *InviteCodeController) CreateInviteCode(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	var req dto.CreateInviteCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("INVALID_JSON", "Invalid JSON format", err), requestID)
+		return
+	}
+
+	response, err := ic.service.CreateInviteCode(c.Request.Context(), &req)
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	entry := newAuditEntry(c, requestID, models.AuditActionInviteCodeCreate, "invite_code", strconv.Itoa(response.ID))
+	entry.Changes = map[string]models.FieldChange{
+		"code":       created(response.Code),
+		"label":      created(response.Label),
+		"max_uses":   created(response.MaxUses),
+		"expires_at": created(response.ExpiresAt),
+		"active":     created(response.Active),
+	}
+	recordMutation(c.Request.Context(), ic.audit, entry)
+
+	utils.SendCreatedResponse(c, "Invite code created successfully", requestID, response)
+}
+
+func (ic *InviteCodeController) ListInviteCodes(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	response, err := ic.service.ListInviteCodes(c.Request.Context())
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	utils.SendOKResponse(c, "Invite codes retrieved successfully", requestID, response)
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/live"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type LiveController struct {
+	broker            *live.Broker
+	heartbeatInterval time.Duration
+}
+
+func NewLiveController(broker *live.Broker) *LiveController {
+	interval, err := time.ParseDuration(config.GetEnv("LIVE_HEARTBEAT_INTERVAL", "15s"))
+	if err != nil || interval <= 0 {
+		interval = 15 * time.Second
+	}
+	return &LiveController{broker: broker, heartbeatInterval: interval}
+}
+
+func (lc *LiveController) Stream(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	events, unsubscribe := lc.broker.Subscribe()
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(lc.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("connected", gin.H{"request_id": requestID})
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event.Payload)
+			return true
+		case t := <-heartbeat.C:
+			c.SSEvent("heartbeat", gin.H{"time": t.UTC().Format(time.RFC3339)})
+			return true
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/metrics"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/service"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type ReferralController struct {
+	service service.ReferralService
+	audit   service.AuditService
+}
+
+func NewReferralController(service service.ReferralService, audit service.AuditService) *ReferralController {
+	return &ReferralController{service: service, audit: audit}
+}
+
+func (rc *ReferralController) CreateReferralCode(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	var req dto.CreateReferralCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("INVALID_JSON", "Invalid JSON format", err), requestID)
+		return
+	}
+
+	response, err := rc.service.CreateReferralCode(c.Request.Context(), &req)
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	entry := newAuditEntry(c, requestID, models.AuditActionReferralCodeCreate, "referral_code", strconv.Itoa(response.ID))
+	entry.Changes = map[string]models.FieldChange{
+		"code":        created(response.Code),
+		"owner":       created(response.Owner),
+		"description": created(response.Description),
+		"active":      created(response.Active),
+	}
+	recordMutation(c.Request.Context(), rc.audit, entry)
+
+	utils.SendCreatedResponse(c, "Referral code created successfully", requestID, response)
+}
+
+func (rc *ReferralController) ListReferralCodes(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	response, err := rc.service.ListReferralCodes(c.Request.Context())
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	utils.SendOKResponse(c, "Referral codes retrieved successfully", requestID, response)
+}
+
+func (rc *ReferralController) AttributionReport(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	observeExport := metrics.TimeExport("attribution")
+	response, err := rc.service.GetAttributionReport(c.Request.Context())
+	observeExport()
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	entry := newAuditEntry(c, requestID, models.AuditActionExportAttribution, "registration", "")
+	if err := rc.audit.Record(c.Request.Context(), entry); err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	utils.SendOKResponse(c, "Attribution report retrieved successfully", requestID, response)
+}
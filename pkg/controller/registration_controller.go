@@ -1,22 +1,35 @@
 package controller
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/metrics"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/service"
 	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
 )
 
+const maxImportBytes = 10 << 20
+
+// ImportBodyLimit caps the whole multipart upload: the file plus room for
+// the mapping field and multipart framing.
+const ImportBodyLimit = maxImportBytes + 1<<20
+
 type RegistrationController struct {
 	service service.RegistrationService
+	audit   service.AuditService
 }
 
-func NewRegistrationController(service service.RegistrationService) *RegistrationController {
-	return &RegistrationController{service: service}
+func NewRegistrationController(service service.RegistrationService, audit service.AuditService) *RegistrationController {
+	return &RegistrationController{service: service, audit: audit}
 }
 
 func (rc *RegistrationController) Register(c *gin.Context) {
@@ -28,19 +41,135 @@ func (rc *RegistrationController) Register(c *gin.Context) {
 		return
 	}
 
-	response, err := rc.service.CreateRegistration(&req)
+	response, err := rc.service.CreateRegistration(c.Request.Context(), &req)
 	if err != nil {
 		utils.HandleErrorResponse(c, err, requestID)
 		return
 	}
 
+	entry := newAuditEntry(c, requestID, models.AuditActionRegistrationCreate, "registration", strconv.Itoa(response.ID))
+	entry.Changes = registrationAuditChanges(response)
+	recordMutation(c.Request.Context(), rc.audit, entry)
+
 	utils.SendCreatedResponse(c, "Registration created successfully", requestID, response)
 }
 
+func (rc *RegistrationController) RegisterBatch(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	var req dto.BatchRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("INVALID_JSON", "Invalid JSON format", err), requestID)
+		return
+	}
+
+	response, err := rc.service.CreateBatchRegistration(c.Request.Context(), &req)
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	if response.GroupID != nil {
+		registrationIDs := make([]int, 0, response.Succeeded)
+		for _, result := range response.Results {
+			if result.Registration != nil {
+				registrationIDs = append(registrationIDs, result.Registration.ID)
+			}
+		}
+
+		entry := newAuditEntry(c, requestID, models.AuditActionRegistrationBatchCreate, "registration_group", strconv.Itoa(*response.GroupID))
+		entry.Changes = map[string]models.FieldChange{
+			"name":                    created(response.GroupName),
+			"primary_registration_id": created(response.PrimaryRegistrationID),
+			"registration_ids":        created(registrationIDs),
+		}
+		entry.Metadata = map[string]interface{}{
+			"mode":      response.Mode,
+			"total":     response.Total,
+			"succeeded": response.Succeeded,
+			"failed":    response.Failed,
+		}
+		recordMutation(c.Request.Context(), rc.audit, entry)
+	}
+
+	utils.SendCreatedResponse(c, "Batch registration processed successfully", requestID, response)
+}
+
+func (rc *RegistrationController) Import(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("MISSING_FILE", "A CSV or XLSX file is required in the 'file' field", err), requestID)
+		return
+	}
+
+	if fileHeader.Size > maxImportBytes {
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("IMPORT_FILE_TOO_LARGE", fmt.Sprintf("Import files are limited to %d bytes", maxImportBytes), nil), requestID)
+		return
+	}
+
+	opts := service.ImportOptions{
+		DryRun:      c.Query("dry_run") == "true",
+		SkipInvalid: c.Query("skip_invalid") == "true",
+	}
+
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".csv":
+		opts.Format = service.ImportFormatCSV
+	case ".xlsx":
+		opts.Format = service.ImportFormatXLSX
+	default:
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("IMPORT_UNSUPPORTED_FORMAT", "Only .csv and .xlsx files can be imported", nil), requestID)
+		return
+	}
+
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			utils.HandleErrorResponse(c, utils.NewBadRequestError("IMPORT_INVALID_MAPPING", "Column mapping must be a JSON object of header to field", err), requestID)
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.HandleErrorResponse(c, utils.NewBadRequestError("IMPORT_READ_ERROR", "Failed to read the uploaded file", err), requestID)
+		return
+	}
+	defer file.Close()
+
+	report, err := rc.service.ImportRegistrations(c.Request.Context(), file, opts)
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	if opts.DryRun {
+		utils.SendOKResponse(c, "Import validated successfully", requestID, report)
+		return
+	}
+
+	if report.Imported > 0 {
+		entry := newAuditEntry(c, requestID, models.AuditActionRegistrationImport, "registration", "")
+		entry.Metadata = map[string]interface{}{
+			"format":           report.Format,
+			"total_rows":       report.TotalRows,
+			"imported":         report.Imported,
+			"invalid_rows":     report.InvalidRows,
+			"registration_ids": report.RegistrationIDs,
+		}
+		recordMutation(c.Request.Context(), rc.audit, entry)
+	}
+
+	utils.SendCreatedResponse(c, "Registrations imported successfully", requestID, report)
+}
+
 func (rc *RegistrationController) DownloadCSV(c *gin.Context) {
 	requestID := utils.GetRequestID(c)
 
-	csvData, err := rc.service.GenerateCSV()
+	observeExport := metrics.TimeExport("csv")
+	csvData, err := rc.service.GenerateCSV(c.Request.Context())
+	observeExport()
 	if err != nil {
 		utils.HandleErrorResponse(c, err, requestID)
 		return
@@ -48,6 +177,18 @@ func (rc *RegistrationController) DownloadCSV(c *gin.Context) {
 
 	filename := fmt.Sprintf("registrations_%s.csv", time.Now().Format("2006-01-02_15-04-05"))
 
+	// Exports are audited before any data leaves the server; if the audit
+	// entry cannot be written the download is refused.
+	entry := newAuditEntry(c, requestID, models.AuditActionExportCSV, "registration", "")
+	entry.Metadata = map[string]interface{}{
+		"filename": filename,
+		"bytes":    len(csvData),
+	}
+	if err := rc.audit.Record(c.Request.Context(), entry); err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
 	c.Header("Content-Description", "File Transfer")
 	c.Header("Content-Disposition", "attachment; filename="+filename)
 	c.Header("Content-Type", "text/csv")
@@ -55,3 +196,41 @@ func (rc *RegistrationController) DownloadCSV(c *gin.Context) {
 
 	c.Data(http.StatusOK, "text/csv", csvData)
 }
+
+func (rc *RegistrationController) Stats(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	observeExport := metrics.TimeExport("stats")
+	stats, err := rc.service.GetStats(c.Request.Context())
+	observeExport()
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	entry := newAuditEntry(c, requestID, models.AuditActionExportStats, "registration", "")
+	entry.Metadata = map[string]interface{}{"generated_on": stats.GeneratedOn}
+	if err := rc.audit.Record(c.Request.Context(), entry); err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	utils.SendOKResponse(c, "Registration statistics retrieved successfully", requestID, stats)
+}
+
+// registrationAuditChanges lists the fields of a new registration that are
+// safe to keep in the audit log. Name, email, phone and designation are
+// left out: audit entries are append-only, so erasure could not remove
+// them.
+func registrationAuditChanges(reg *dto.RegistrationResponse) map[string]models.FieldChange {
+	return map[string]models.FieldChange{
+		"org_name":      created(reg.OrgName),
+		"mkt_source":    created(reg.MktSource),
+		"food_pref":     created(reg.FoodPref),
+		"t_shirt":       created(reg.TShirt),
+		"utm_source":    created(reg.UTMSource),
+		"utm_campaign":  created(reg.UTMCampaign),
+		"referral_code": created(reg.ReferralCode),
+		"invite_code":   created(reg.InviteCode),
+	}
+}
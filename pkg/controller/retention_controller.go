@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/service"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type RetentionController struct {
+	service service.RetentionService
+	audit   service.AuditService
+}
+
+func NewRetentionController(service service.RetentionService, audit service.AuditService) *RetentionController {
+	return &RetentionController{service: service, audit: audit}
+}
+
+func (rc *RetentionController) Preview(c *gin.Context) {
+	requestID := utils.GetRequestID(c)
+
+	response, err := rc.service.Preview(c.Request.Context())
+	if err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	// The preview lists the registrations due for purging, so it is
+	// audited like an export and refused if that entry cannot be written.
+	entry := newAuditEntry(c, requestID, models.AuditActionRetentionPreview, "registration", "")
+	entry.Metadata = map[string]interface{}{
+		"due":                    response.Due,
+		"registrations_affected": response.RegistrationsAffected,
+		"registration_ids":       response.RegistrationIDs,
+	}
+	if response.Cutoff != nil {
+		entry.Metadata["cutoff"] = *response.Cutoff
+	}
+	if err := rc.audit.Record(c.Request.Context(), entry); err != nil {
+		utils.HandleErrorResponse(c, err, requestID)
+		return
+	}
+
+	utils.SendOKResponse(c, "Retention preview generated successfully", requestID, response)
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+const (
+	DefaultAuditLogLimit = 50
+	MaxAuditLogLimit     = 500
+)
+
+type AuditLogQuery struct {
+	Actor      string `form:"actor"`
+	Action     string `form:"action"`
+	EntityType string `form:"entity_type"`
+	EntityID   string `form:"entity_id"`
+	RequestID  string `form:"request_id"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Limit      int    `form:"limit"`
+	Offset     int    `form:"offset"`
+}
+
+func (q *AuditLogQuery) ToFilter() (models.AuditLogFilter, []utils.ValidationError) {
+	var errors []utils.ValidationError
+
+	filter := models.AuditLogFilter{
+		Actor:      q.Actor,
+		Action:     q.Action,
+		EntityType: q.EntityType,
+		EntityID:   q.EntityID,
+		RequestID:  q.RequestID,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAuditLogLimit
+	}
+	if filter.Limit > MaxAuditLogLimit {
+		errors = append(errors, utils.ValidationError{
+			Field:   "limit",
+			Message: "Limit cannot exceed 500",
+		})
+	}
+	if filter.Offset < 0 {
+		errors = append(errors, utils.ValidationError{
+			Field:   "offset",
+			Message: "Offset cannot be negative",
+		})
+	}
+
+	if q.From != "" {
+		from, err := time.Parse(time.RFC3339, q.From)
+		if err != nil {
+			errors = append(errors, utils.ValidationError{
+				Field:   "from",
+				Message: "From must be an RFC 3339 timestamp",
+			})
+		}
+		filter.From = &from
+	}
+	if q.To != "" {
+		to, err := time.Parse(time.RFC3339, q.To)
+		if err != nil {
+			errors = append(errors, utils.ValidationError{
+				Field:   "to",
+				Message: "To must be an RFC 3339 timestamp",
+			})
+		}
+		filter.To = &to
+	}
+
+	return filter, errors
+}
+
+type AuditLogResponse struct {
+	ID         int64                         `json:"id"`
+	Actor      string                        `json:"actor"`
+	Action     string                        `json:"action"`
+	EntityType string                        `json:"entity_type"`
+	EntityID   string                        `json:"entity_id"`
+	RequestID  string                        `json:"request_id"`
+	IPAddress  string                        `json:"ip_address"`
+	Changes    map[string]models.FieldChange `json:"changes"`
+	Metadata   map[string]interface{}        `json:"metadata"`
+	CreatedOn  string                        `json:"created_on"`
+}
+
+type AuditLogListResponse struct {
+	AuditLogs []AuditLogResponse `json:"audit_logs"`
+	Total     int                `json:"total"`
+	Limit     int                `json:"limit"`
+	Offset    int                `json:"offset"`
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+const (
+	BatchModeAtomic  = "atomic"
+	BatchModePartial = "partial"
+)
+
+type BatchRegistrationRequest struct {
+	GroupName           string                      `json:"group_name" binding:"required,max=255"`
+	PrimaryContactIndex int                         `json:"primary_contact_index"`
+	Mode                string                      `json:"mode,omitempty" binding:"omitempty,oneof=atomic partial"`
+	Registrations       []CreateRegistrationRequest `json:"registrations" binding:"required,min=1,max=100"`
+}
+
+func (r *BatchRegistrationRequest) Validate() []utils.ValidationError {
+	var errors []utils.ValidationError
+
+	if strings.TrimSpace(r.GroupName) == "" {
+		errors = append(errors, utils.ValidationError{
+			Field:   "group_name",
+			Message: "Group name cannot be empty",
+		})
+	}
+
+	if r.PrimaryContactIndex < 0 || r.PrimaryContactIndex >= len(r.Registrations) {
+		errors = append(errors, utils.ValidationError{
+			Field:   "primary_contact_index",
+			Message: "Primary contact must refer to one of the registrations",
+		})
+	}
+
+	return errors
+}
+
+func (r *BatchRegistrationRequest) IsPartial() bool {
+	return r.Mode == BatchModePartial
+}
+
+type BatchItemResult struct {
+	Index        int                     `json:"index"`
+	Status       string                  `json:"status"`
+	Registration *RegistrationResponse   `json:"registration,omitempty"`
+	Code         string                  `json:"code,omitempty"`
+	Message      string                  `json:"message,omitempty"`
+	Errors       []utils.ValidationError `json:"errors,omitempty"`
+}
+
+type BatchRegistrationResponse struct {
+	GroupID               *int              `json:"group_id"`
+	GroupName             string            `json:"group_name"`
+	PrimaryRegistrationID *int              `json:"primary_registration_id"`
+	Mode                  string            `json:"mode"`
+	Total                 int               `json:"total"`
+	Succeeded             int               `json:"succeeded"`
+	Failed                int               `json:"failed"`
+	Results               []BatchItemResult `json:"results"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type DataSubjectRequest struct {
+	Email string `json:"email,omitempty" binding:"max=255"`
+	Phone string `json:"phone,omitempty" binding:"max=13"`
+}
+
+func (r *DataSubjectRequest) Validate() []utils.ValidationError {
+	var errors []utils.ValidationError
+
+	r.Email = strings.TrimSpace(r.Email)
+	r.Phone = strings.TrimSpace(r.Phone)
+
+	if r.Email == "" && r.Phone == "" {
+		errors = append(errors, utils.ValidationError{
+			Field:   "email",
+			Message: "Either email or phone is required",
+		})
+	}
+
+	return errors
+}
+
+type RegistrationGroupResponse struct {
+	ID                    int    `json:"id"`
+	Name                  string `json:"name"`
+	PrimaryRegistrationID *int   `json:"primary_registration_id"`
+	CreatedOn             string `json:"created_on"`
+}
+
+type CachedResponseSummary struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	Method         string `json:"method"`
+	Path           string `json:"path"`
+	CreatedOn      string `json:"created_on"`
+	ExpiresAt      string `json:"expires_at"`
+}
+
+type DataSubjectExportResponse struct {
+	Registrations   []RegistrationResponse      `json:"registrations"`
+	Groups          []RegistrationGroupResponse `json:"groups"`
+	AuditLogs       []AuditLogResponse          `json:"audit_logs"`
+	CachedResponses []CachedResponseSummary     `json:"cached_responses"`
+	GeneratedOn     string                      `json:"generated_on"`
+}
+
+type DataSubjectErasureResponse struct {
+	ErasedRegistrationIDs []int  `json:"erased_registration_ids"`
+	PurgedCachedResponses int64  `json:"purged_cached_responses"`
+	ErasedOn              string `json:"erased_on"`
+}
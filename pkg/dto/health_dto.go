@@ -0,0 +1,17 @@
+package dto
+
+const (
+	HealthStatusPass = "pass"
+	HealthStatusFail = "fail"
+)
+
+type HealthCheckResult struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+type HealthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type ImportRowError struct {
+	Row    int                     `json:"row"`
+	Code   string                  `json:"code"`
+	Errors []utils.ValidationError `json:"errors"`
+}
+
+type ImportReportResponse struct {
+	Format          string            `json:"format"`
+	DryRun          bool              `json:"dry_run"`
+	ColumnMapping   map[string]string `json:"column_mapping"`
+	TotalRows       int               `json:"total_rows"`
+	ValidRows       int               `json:"valid_rows"`
+	InvalidRows     int               `json:"invalid_rows"`
+	Imported        int               `json:"imported"`
+	RegistrationIDs []int             `json:"registration_ids"`
+	RowErrors       []ImportRowError  `json:"row_errors"`
+}
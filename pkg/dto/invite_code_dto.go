@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type CreateInviteCodeRequest struct {
+	Code      string `json:"code" binding:"required,min=3,max=64"`
+	Label     string `json:"label,omitempty" binding:"max=255"`
+	MaxUses   *int   `json:"max_uses,omitempty" binding:"omitempty,min=1"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+func (r *CreateInviteCodeRequest) Validate() []utils.ValidationError {
+	var errors []utils.ValidationError
+
+	if !codePattern.MatchString(NormalizeCode(r.Code)) {
+		errors = append(errors, utils.ValidationError{
+			Field:   "code",
+			Message: "Code may only contain letters, digits, '-' and '_'",
+		})
+	}
+
+	if r.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, r.ExpiresAt)
+		if err != nil {
+			errors = append(errors, utils.ValidationError{
+				Field:   "expires_at",
+				Message: "Expiry must be an RFC 3339 timestamp",
+			})
+		} else if !expiresAt.After(time.Now()) {
+			errors = append(errors, utils.ValidationError{
+				Field:   "expires_at",
+				Message: "Expiry must be in the future",
+			})
+		}
+	}
+
+	return errors
+}
+
+type InviteCodeResponse struct {
+	ID        int     `json:"id"`
+	Code      string  `json:"code"`
+	Label     string  `json:"label"`
+	MaxUses   *int    `json:"max_uses"`
+	UsedCount int     `json:"used_count"`
+	Remaining *int    `json:"remaining"`
+	ExpiresAt *string `json:"expires_at"`
+	Active    bool    `json:"active"`
+	CreatedOn string  `json:"created_on"`
+}
+
+type InviteCodeListResponse struct {
+	InviteCodes []InviteCodeResponse `json:"invite_codes"`
+	Total       int                  `json:"total"`
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,64}$`)
+
+type CreateReferralCodeRequest struct {
+	Code        string `json:"code" binding:"required,min=3,max=64"`
+	Owner       string `json:"owner" binding:"required,max=255"`
+	Description string `json:"description,omitempty" binding:"max=255"`
+}
+
+func (r *CreateReferralCodeRequest) Validate() []utils.ValidationError {
+	var errors []utils.ValidationError
+
+	if !codePattern.MatchString(NormalizeCode(r.Code)) {
+		errors = append(errors, utils.ValidationError{
+			Field:   "code",
+			Message: "Code may only contain letters, digits, '-' and '_'",
+		})
+	}
+
+	if strings.TrimSpace(r.Owner) == "" {
+		errors = append(errors, utils.ValidationError{
+			Field:   "owner",
+			Message: "Owner cannot be empty",
+		})
+	}
+
+	return errors
+}
+
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+type ReferralCodeResponse struct {
+	ID          int    `json:"id"`
+	Code        string `json:"code"`
+	Owner       string `json:"owner"`
+	Description string `json:"description"`
+	Active      bool   `json:"active"`
+	CreatedOn   string `json:"created_on"`
+}
+
+type ReferralCodeListResponse struct {
+	ReferralCodes []ReferralCodeResponse `json:"referral_codes"`
+	Total         int                    `json:"total"`
+}
+
+type AttributionReportResponse struct {
+	Total          int          `json:"total"`
+	BySource       []CountEntry `json:"by_source"`
+	ByMedium       []CountEntry `json:"by_medium"`
+	ByCampaign     []CountEntry `json:"by_campaign"`
+	ByReferralCode []CountEntry `json:"by_referral_code"`
+}
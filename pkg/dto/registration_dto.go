@@ -15,6 +15,18 @@ type CreateRegistrationRequest struct {
 	MktSource   string `json:"mkt_source,omitempty"`
 	FoodPref    string `json:"food_pref" binding:"required"`
 	TShirt      string `json:"t_shirt" binding:"required,oneof=S M L XL XXL XXXL"`
+	InviteCode  string `json:"invite_code,omitempty" binding:"max=64"`
+	Attribution
+}
+
+type Attribution struct {
+	UTMSource    string `json:"utm_source,omitempty" binding:"max=255"`
+	UTMMedium    string `json:"utm_medium,omitempty" binding:"max=255"`
+	UTMCampaign  string `json:"utm_campaign,omitempty" binding:"max=255"`
+	UTMTerm      string `json:"utm_term,omitempty" binding:"max=255"`
+	UTMContent   string `json:"utm_content,omitempty" binding:"max=255"`
+	Referrer     string `json:"referrer,omitempty" binding:"max=2048"`
+	ReferralCode string `json:"referral_code,omitempty" binding:"max=64"`
 }
 
 func (r *CreateRegistrationRequest) Validate() []utils.ValidationError {
@@ -47,10 +59,33 @@ type RegistrationResponse struct {
 	MktSource   string `json:"mkt_source"`
 	FoodPref    string `json:"food_pref"`
 	TShirt      string `json:"t_shirt"`
-	CreatedOn   string `json:"created_on"`
+	InviteCode  string `json:"invite_code,omitempty"`
+	Attribution
+	CreatedOn string `json:"created_on"`
 }
 
 type RegistrationListResponse struct {
 	Registrations []RegistrationResponse `json:"registrations"`
 	Total         int                    `json:"total"`
 }
+
+type CountEntry struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type PeriodCountEntry struct {
+	Period string `json:"period"`
+	Count  int    `json:"count"`
+}
+
+type RegistrationStatsResponse struct {
+	Total       int                `json:"total"`
+	ByFoodPref  []CountEntry       `json:"by_food_pref"`
+	ByTShirt    []CountEntry       `json:"by_t_shirt"`
+	ByMktSource []CountEntry       `json:"by_mkt_source"`
+	ByOrgName   []CountEntry       `json:"by_org_name"`
+	PerDay      []PeriodCountEntry `json:"per_day"`
+	PerHour     []PeriodCountEntry `json:"per_hour"`
+	GeneratedOn string             `json:"generated_on"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type RetentionPolicyResponse struct {
+	Enabled       bool    `json:"enabled"`
+	EventEndDate  *string `json:"event_end_date"`
+	RetentionDays int     `json:"retention_days"`
+	Action        string  `json:"action"`
+}
+
+type RetentionPreviewResponse struct {
+	Policy                RetentionPolicyResponse `json:"policy"`
+	Due                   bool                    `json:"due"`
+	Cutoff                *string                 `json:"cutoff"`
+	RegistrationsAffected int                     `json:"registrations_affected"`
+	RegistrationIDs       []int                   `json:"registration_ids"`
+	OldestCreatedOn       *string                 `json:"oldest_created_on"`
+	NewestCreatedOn       *string                 `json:"newest_created_on"`
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+// itemValidator applies the same `binding` tags as gin but is private to
+// this package, so it can report JSON field names without reconfiguring
+// gin's shared validator while requests are being bound.
+var itemValidator = newItemValidator()
+
+func newItemValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// ValidateStruct runs the `binding` tag rules that ShouldBindJSON applies
+// and reports failures by their JSON field names. It is used for items
+// nested in a request, where a binding failure should be reported per
+// item rather than rejecting the whole body.
+func ValidateStruct(obj interface{}) []utils.ValidationError {
+	err := itemValidator.Struct(obj)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []utils.ValidationError{{Field: "", Message: err.Error()}}
+	}
+
+	validationErrors := make([]utils.ValidationError, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		validationErrors = append(validationErrors, utils.ValidationError{
+			Field:   fe.Field(),
+			Message: validationMessage(fe),
+		})
+	}
+	return validationErrors
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "This field is required"
+	case "min":
+		return fmt.Sprintf("Must be at least %s characters", fe.Param())
+	case "max":
+		return fmt.Sprintf("Must be at most %s characters", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("Must be one of: %s", fe.Param())
+	default:
+		return fmt.Sprintf("Failed %s validation", fe.Tag())
+	}
+}
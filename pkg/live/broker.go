@@ -0,0 +1,143 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	RegistrationChannel = "registration_events"
+
+	subscriberBuffer = 16
+	reconnectDelay   = 5 * time.Second
+)
+
+type Event struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Broker holds a dedicated connection that LISTENs on the registration
+// channel and fans every notification out to the connected SSE clients.
+// Because notifications come from Postgres, inserts made by any server
+// instance reach every instance's subscribers.
+type Broker struct {
+	db          *pgxpool.Pool
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+func NewBroker(db *pgxpool.Pool) *Broker {
+	return &Broker{
+		db:          db,
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+func (b *Broker) Start(ctx context.Context) {
+	go func() {
+		for {
+			if err := b.listen(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("Live update listener stopped, reconnecting")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reconnectDelay):
+			}
+		}
+	}()
+}
+
+// Subscribe returns a channel of events and a function that releases it.
+// The channel is closed when the broker is closed; after that, new
+// subscriptions receive an already-closed channel.
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.closed {
+		close(ch)
+	} else {
+		b.subscribers[ch] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+		b.mu.Unlock()
+	}
+
+	return ch, unsubscribe
+}
+
+// Close ends every subscription so open streams finish and the server can
+// shut down. It does not stop the listener; cancel Start's context for that.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (b *Broker) listen(ctx context.Context) error {
+	conn, err := b.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+RegistrationChannel); err != nil {
+		return err
+	}
+
+	log.Info().Str("channel", RegistrationChannel).Msg("Listening for live updates")
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var envelope struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(notification.Payload), &envelope); err != nil {
+			log.Warn().Err(err).Str("channel", notification.Channel).Msg("Discarding malformed notification")
+			continue
+		}
+
+		b.publish(Event{
+			Type:    envelope.Type,
+			Payload: json.RawMessage(notification.Payload),
+		})
+	}
+}
+
+func (b *Broker) publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Warn().Str("type", event.Type).Msg("Dropping live update for slow subscriber")
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "txqr"
+
+const (
+	SourceSingle = "single"
+	SourceBatch  = "batch"
+	SourceImport = "import"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RegistrationsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_created_total",
+		Help:      "Registrations stored, by how they were submitted.",
+	}, []string{"source"})
+
+	DuplicatesRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registration_duplicates_rejected_total",
+		Help:      "Registrations rejected as duplicates, by error code.",
+	}, []string{"code"})
+
+	ExportDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "export_duration_seconds",
+		Help:      "Time taken to build each kind of export.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"export"})
+)
+
+// TimeExport starts timing an export and returns the function that
+// records it.
+func TimeExport(export string) func() {
+	timer := prometheus.NewTimer(ExportDuration.WithLabelValues(export))
+	return func() { timer.ObserveDuration() }
+}
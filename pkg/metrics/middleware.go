@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels requests that hit no route, so probing random
+// paths cannot create unbounded label values.
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a method outside knownMethods, for the
+// same reason.
+const otherMethod = "OTHER"
+
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// Middleware records the count and latency of every request, labelled by
+// the route pattern rather than the raw path and by a fixed set of
+// methods.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+		if !knownMethods[method] {
+			method = otherMethod
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		HTTPRequests.WithLabelValues(method, route, status).Inc()
+		HTTPRequestDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler serves the default registry. It accepts only the metrics key,
+// sent as a bearer token, so scrapers never hold the API gateway key.
+func Handler(metricsKey string) gin.HandlerFunc {
+	handler := promhttp.Handler()
+
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(metricsKey)) != 1 {
+			requestID := utils.GetRequestID(c)
+			utils.HandleErrorResponse(c, utils.NewUnauthorizedError("INVALID_METRICS_KEY", "A valid metrics key is required", nil), requestID)
+			c.Abort()
+			return
+		}
+
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddlewareBoundsMethodAndRouteLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/stats", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, method := range []string{"PROPFIND", "BREW", "GET"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/stats", nil))
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/no/such/path", nil))
+
+	if got := testutil.ToFloat64(HTTPRequests.WithLabelValues(otherMethod, unmatchedRoute, "404")); got != 2 {
+		t.Errorf("OTHER requests = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(HTTPRequests.WithLabelValues(http.MethodGet, "/stats", "200")); got != 1 {
+		t.Errorf("GET /stats requests = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(HTTPRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")); got != 1 {
+		t.Errorf("unmatched GET requests = %v, want 1", got)
+	}
+}
+
+func TestHandlerRequiresTheMetricsKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/metrics", Handler("metrics-key"))
+
+	for token, want := range map[string]int{"": http.StatusUnauthorized, "wrong": http.StatusUnauthorized, "metrics-key": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("token %q: got %d, want %d", token, w.Code, want)
+		}
+	}
+}
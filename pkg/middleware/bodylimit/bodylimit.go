@@ -0,0 +1,36 @@
+package bodylimit
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+// MaxBodySizeMiddleware caps request bodies at limit bytes, or at the
+// override for the matched route. Bodies that declare a larger
+// Content-Length are rejected up front; others fail with a 413 when the
+// handler reads past the limit.
+func MaxBodySizeMiddleware(limit int64, overrides map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		routeLimit := limit
+		if override, ok := overrides[c.FullPath()]; ok {
+			routeLimit = override
+		}
+
+		if c.Request.ContentLength > routeLimit {
+			utils.HandleErrorResponse(c, utils.NewPayloadTooLargeError("REQUEST_BODY_TOO_LARGE", fmt.Sprintf("Request bodies are limited to %d bytes", routeLimit), nil), utils.GetRequestID(c))
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, routeLimit)
+		c.Next()
+	}
+}
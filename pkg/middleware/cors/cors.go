@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
 )
 
 func SetupCORS() gin.HandlerFunc {
@@ -17,11 +18,16 @@ func SetupCORS() gin.HandlerFunc {
 		}
 
 		ctx.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Authorization, X-Api-Key, X-Request-ID")
+		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Authorization, X-Api-Key, X-Request-ID, Idempotency-Key, traceparent, tracestate")
 		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		ctx.Writer.Header().Set("Access-Control-Max-Age", "86400")
 
 		if ctx.Request.Method == "OPTIONS" {
+			if origin != "" && !isOriginAllowed(origin, allowedOrigins) {
+				utils.HandleErrorResponse(ctx, utils.NewForbiddenError("CORS_ORIGIN_NOT_ALLOWED", "This origin is not allowed", nil), utils.GetRequestID(ctx))
+				ctx.Abort()
+				return
+			}
 			ctx.AbortWithStatus(204)
 			return
 		}
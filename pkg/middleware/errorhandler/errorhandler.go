@@ -0,0 +1,64 @@
+package errorhandler
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/rs/zerolog"
+)
+
+// RecoveryMiddleware turns a panic into a logged 500 in the standard error
+// envelope. It replaces gin.Recovery, whose response has no body.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// The client is gone; there is nobody to answer.
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			zerolog.Ctx(c.Request.Context()).Error().
+				Str("panic", fmt.Sprint(recovered)).
+				Bytes("stack", debug.Stack()).
+				Str("path", c.Request.URL.Path).
+				Msg("Recovered from panic")
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			utils.HandleErrorResponse(c, utils.NewInternalServerError("INTERNAL_ERROR", "An unexpected error occurred", nil), utils.GetRequestID(c))
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
+
+// ErrorMiddleware answers for handlers that recorded an error with c.Error
+// but wrote no response.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+		utils.HandleErrorResponse(c, c.Errors.Last().Err, utils.GetRequestID(c))
+	}
+}
+
+func NoRoute(c *gin.Context) {
+	utils.HandleErrorResponse(c, utils.NewNotFoundError("ROUTE_NOT_FOUND", "This route does not exist", nil), utils.GetRequestID(c))
+}
+
+func NoMethod(c *gin.Context) {
+	utils.HandleErrorResponse(c, utils.NewMethodNotAllowedError("METHOD_NOT_ALLOWED", "This method is not allowed on this route", nil), utils.GetRequestID(c))
+}
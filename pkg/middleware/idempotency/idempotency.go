@@ -0,0 +1,146 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/rs/zerolog"
+)
+
+const (
+	HeaderName     = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the stored response when a request is
+// retried with the same Idempotency-Key and body. Reusing a key with a
+// different body, or while the first request is still running, is a
+// conflict. Server errors and cancelled requests are not stored, so they
+// can be retried.
+//
+// A request holds its key for IDEMPOTENCY_LEASE. If it never settles the
+// key (the process crashed or was killed), a retry with the same body can
+// take the key over once the lease has run out; it should outlast the
+// slowest request.
+func IdempotencyMiddleware(repo repository.IdempotencyRepository) gin.HandlerFunc {
+	ttl, err := time.ParseDuration(config.GetEnv("IDEMPOTENCY_KEY_TTL", "24h"))
+	if err != nil || ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	lease, err := time.ParseDuration(config.GetEnv("IDEMPOTENCY_LEASE", "5m"))
+	if err != nil || lease <= 0 {
+		lease = 5 * time.Minute
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderName)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		requestID := utils.GetRequestID(c)
+
+		if len(key) > maxKeyLength {
+			utils.HandleErrorResponse(c, utils.NewBadRequestError("INVALID_IDEMPOTENCY_KEY", "Idempotency-Key must be at most 255 characters", nil), requestID)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			utils.HandleErrorResponse(c, utils.NewBadRequestError("INVALID_BODY", "Failed to read request body", err), requestID)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.FullPath() + "\n"))
+		hash.Write(body)
+
+		record := &models.IdempotencyRecord{
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.FullPath(),
+			RequestHash: hex.EncodeToString(hash.Sum(nil)),
+		}
+
+		stored, reserved, err := repo.Reserve(c.Request.Context(), record, ttl, lease)
+		if err != nil {
+			utils.HandleErrorResponse(c, err, requestID)
+			c.Abort()
+			return
+		}
+
+		if !reserved {
+			switch {
+			case stored.RequestHash != record.RequestHash:
+				utils.HandleErrorResponse(c, utils.NewConflictError("IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used with a different request", nil), requestID)
+			case !stored.IsComplete():
+				utils.HandleErrorResponse(c, utils.NewConflictError("IDEMPOTENCY_REQUEST_IN_PROGRESS", "A request with this Idempotency-Key is still being processed", nil), requestID)
+			default:
+				c.Header(ReplayedHeader, "true")
+				c.Data(*stored.StatusCode, stored.ContentType, stored.ResponseBody)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Settle the key even if the client has gone away, so a retry
+		// never finds it stuck in progress.
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := recorder.Status()
+		if retryable(status) {
+			if err := repo.Release(ctx, record); err != nil {
+				zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to release idempotency key")
+			}
+			return
+		}
+
+		record.StatusCode = &status
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.ResponseBody = recorder.body.Bytes()
+		if err := repo.Complete(ctx, record); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to store idempotent response")
+		}
+	}
+}
+
+// retryable reports whether a response says nothing final about the
+// request: the server failed, or the client went away mid-request.
+func retryable(status int) bool {
+	return status >= http.StatusInternalServerError || status == utils.StatusClientClosedRequest
+}
@@ -0,0 +1,214 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type storedKey struct {
+	method, path, key string
+}
+
+// fakeRepo keeps reservations in memory with the repository's semantics:
+// the first Reserve wins and later ones get the stored record, unless it
+// was left in progress past its lease by a request with the same body.
+type fakeRepo struct {
+	records  map[storedKey]*models.IdempotencyRecord
+	released int
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{records: map[storedKey]*models.IdempotencyRecord{}}
+}
+
+func keyOf(record *models.IdempotencyRecord) storedKey {
+	return storedKey{record.Method, record.Path, record.Key}
+}
+
+func (r *fakeRepo) Reserve(ctx context.Context, record *models.IdempotencyRecord, ttl, lease time.Duration) (*models.IdempotencyRecord, bool, error) {
+	if existing, ok := r.records[keyOf(record)]; ok {
+		abandoned := !existing.IsComplete() && time.Now().After(*existing.LockedUntil) && existing.RequestHash == record.RequestHash
+		if !abandoned {
+			copied := *existing
+			return &copied, false, nil
+		}
+	}
+	lockedUntil := time.Now().Add(lease)
+	record.LockedUntil = &lockedUntil
+	copied := *record
+	r.records[keyOf(record)] = &copied
+	return record, true, nil
+}
+
+func (r *fakeRepo) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	copied := *record
+	r.records[keyOf(record)] = &copied
+	return nil
+}
+
+func (r *fakeRepo) Release(ctx context.Context, record *models.IdempotencyRecord) error {
+	r.released++
+	delete(r.records, keyOf(record))
+	return nil
+}
+
+func (r *fakeRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// testRouter serves POST /register, answering with the next status in
+// statuses and counting how often the handler actually ran.
+func testRouter(repo *fakeRepo, statuses ...int) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	router := gin.New()
+	router.POST("/register", IdempotencyMiddleware(repo), func(c *gin.Context) {
+		status := statuses[calls]
+		calls++
+		c.JSON(status, gin.H{"call": calls})
+	})
+	return router, &calls
+}
+
+// requestHash matches the hash the middleware stores for a request.
+func requestHash(method, path, body string) string {
+	sum := sha256.Sum256([]byte(method + " " + path + "\n" + body))
+	return hex.EncodeToString(sum[:])
+}
+
+func send(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(HeaderName, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestReplaysTheStoredResponse(t *testing.T) {
+	router, calls := testRouter(newFakeRepo(), http.StatusCreated, http.StatusCreated)
+
+	first := send(router, "key-1", `{"a":1}`)
+	second := send(router, "key-1", `{"a":1}`)
+
+	if *calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", *calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("replay is missing the %s header", ReplayedHeader)
+	}
+}
+
+func TestRejectsAKeyReusedWithADifferentBody(t *testing.T) {
+	router, calls := testRouter(newFakeRepo(), http.StatusCreated, http.StatusCreated)
+
+	send(router, "key-1", `{"a":1}`)
+	second := send(router, "key-1", `{"a":2}`)
+
+	if *calls != 1 || second.Code != http.StatusConflict || !strings.Contains(second.Body.String(), "IDEMPOTENCY_KEY_REUSED") {
+		t.Errorf("got %d %s after %d handler calls, want 409 IDEMPOTENCY_KEY_REUSED", second.Code, second.Body, *calls)
+	}
+}
+
+// inProgress stores a reservation for key-1 whose lease ends at
+// lockedUntil, as a request that is still running (or has died) leaves it.
+func inProgress(repo *fakeRepo, body string, lockedUntil time.Time) {
+	repo.records[storedKey{http.MethodPost, "/register", "key-1"}] = &models.IdempotencyRecord{
+		Key: "key-1", Method: http.MethodPost, Path: "/register", RequestHash: requestHash(http.MethodPost, "/register", body), LockedUntil: &lockedUntil,
+	}
+}
+
+func TestRejectsAKeyStillInProgress(t *testing.T) {
+	repo := newFakeRepo()
+	router, calls := testRouter(repo, http.StatusCreated)
+	inProgress(repo, `{"a":1}`, time.Now().Add(time.Minute))
+
+	w := send(router, "key-1", `{"a":1}`)
+
+	if *calls != 0 || w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "IDEMPOTENCY_REQUEST_IN_PROGRESS") {
+		t.Errorf("got %d %s, want 409 IDEMPOTENCY_REQUEST_IN_PROGRESS", w.Code, w.Body)
+	}
+}
+
+func TestTakesOverAKeyAbandonedPastItsLease(t *testing.T) {
+	repo := newFakeRepo()
+	router, calls := testRouter(repo, http.StatusCreated, http.StatusCreated)
+	inProgress(repo, `{"a":1}`, time.Now().Add(-time.Second))
+
+	retry := send(router, "key-1", `{"a":1}`)
+	replay := send(router, "key-1", `{"a":1}`)
+
+	if *calls != 1 || retry.Code != http.StatusCreated {
+		t.Fatalf("retry got %d after %d handler calls, want a fresh 201", retry.Code, *calls)
+	}
+	if replay.Header().Get(ReplayedHeader) != "true" || replay.Body.String() != retry.Body.String() {
+		t.Errorf("second retry was not replayed from the taken-over key")
+	}
+}
+
+func TestDoesNotTakeOverAnAbandonedKeyWithADifferentBody(t *testing.T) {
+	repo := newFakeRepo()
+	router, calls := testRouter(repo, http.StatusCreated)
+	inProgress(repo, `{"a":1}`, time.Now().Add(-time.Second))
+
+	w := send(router, "key-1", `{"a":2}`)
+
+	if *calls != 0 || w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "IDEMPOTENCY_KEY_REUSED") {
+		t.Errorf("got %d %s, want 409 IDEMPOTENCY_KEY_REUSED", w.Code, w.Body)
+	}
+}
+
+func TestReleasesTheKeyWhenTheOutcomeIsNotFinal(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusServiceUnavailable, utils.StatusClientClosedRequest} {
+		repo := newFakeRepo()
+		router, calls := testRouter(repo, status, http.StatusCreated)
+
+		send(router, "key-1", `{"a":1}`)
+		retry := send(router, "key-1", `{"a":1}`)
+
+		if repo.released != 1 {
+			t.Errorf("status %d: released %d keys, want 1", status, repo.released)
+		}
+		if *calls != 2 || retry.Code != http.StatusCreated {
+			t.Errorf("status %d: retry got %d after %d handler calls, want a fresh 201", status, retry.Code, *calls)
+		}
+	}
+}
+
+func TestStoresClientErrors(t *testing.T) {
+	repo := newFakeRepo()
+	router, calls := testRouter(repo, http.StatusBadRequest, http.StatusCreated)
+
+	send(router, "key-1", `{"a":1}`)
+	retry := send(router, "key-1", `{"a":1}`)
+
+	if *calls != 1 || retry.Code != http.StatusBadRequest || repo.released != 0 {
+		t.Errorf("retry got %d after %d handler calls, want the stored 400", retry.Code, *calls)
+	}
+}
+
+func TestPassesThroughWithoutAKey(t *testing.T) {
+	router, calls := testRouter(newFakeRepo(), http.StatusCreated, http.StatusCreated)
+
+	send(router, "", `{"a":1}`)
+	send(router, "", `{"a":1}`)
+
+	if *calls != 2 {
+		t.Errorf("handler ran %d times, want 2", *calls)
+	}
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AccessLogMiddleware stores a logger carrying the request ID (and trace
+// ID, when traced) in the request context, for zerolog.Ctx in service and
+// repository code. It writes one access line per request once the
+// handler has finished. It must run after RequestIDMiddleware.
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		loggerCtx := log.With().Str("request_id", utils.GetRequestID(c))
+		if spanCtx := trace.SpanContextFromContext(c.Request.Context()); spanCtx.IsValid() {
+			loggerCtx = loggerCtx.Str("trace_id", spanCtx.TraceID().String())
+		}
+		logger := loggerCtx.Logger()
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context()))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		event := logger.WithLevel(accessLevel(status))
+		if errs := c.Errors.ByType(gin.ErrorTypeAny); len(errs) > 0 {
+			event = event.Str("errors", errs.String())
+		}
+		event.
+			Str("method", c.Request.Method).
+			Str("route", route).
+			Str("path", c.Request.URL.Path).
+			Int("status", status).
+			Float64("latency_ms", float64(time.Since(start).Microseconds())/1000).
+			Int("bytes", c.Writer.Size()).
+			Str("client_ip", c.ClientIP()).
+			Str("user_agent", c.Request.UserAgent()).
+			Str("api_key_name", c.GetString(utils.APIKeyNameContextKey)).
+			Msg("request")
+	}
+}
+
+func accessLevel(status int) zerolog.Level {
+	switch {
+	case status >= 500:
+		return zerolog.ErrorLevel
+	case status >= 400:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.InfoLevel
+	}
+}
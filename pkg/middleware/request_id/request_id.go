@@ -1,15 +1,42 @@
 package request_id
 
 import (
+	"regexp"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const maxRequestIDLength = 128
+
+// Incoming IDs end up in headers, logs and audit rows, so only a safe
+// character set is accepted.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+// RequestIDMiddleware keeps a well-formed X-Request-ID from the caller, or
+// replaces it with a new UUID, and stores it in the gin context for
+// utils.GetRequestID. It must run before any middleware that responds.
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := utils.GetRequestID(c)
-		c.Set("request_id", requestID)
+		requestID := c.GetHeader("X-Request-ID")
+		if !validRequestID(requestID) {
+			if requestID != "" {
+				log.Debug().Int("length", len(requestID)).Msg("Replacing malformed X-Request-ID")
+			}
+			requestID = uuid.New().String()
+		}
+
+		c.Set(utils.RequestIDContextKey, requestID)
 		c.Header("X-Request-ID", requestID)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("http.request.id", requestID))
 		c.Next()
 	}
 }
+
+func validRequestID(requestID string) bool {
+	return requestID != "" && len(requestID) <= maxRequestIDLength && requestIDPattern.MatchString(requestID)
+}
@@ -1,57 +1,118 @@
 package security
 
 import (
-    "net/http"
+    "crypto/subtle"
     "os"
+    "strings"
     
     "github.com/gin-gonic/gin"
+    "github.com/iamsuteerth/tx-qr-tool-backend/utils"
+    "github.com/rs/zerolog"
     "github.com/rs/zerolog/log"
 )
 
-func APIKeyAuthMiddleware() gin.HandlerFunc {
-    apiKey := os.Getenv("API_GATEWAY_KEY")
+// publicPaths are probed by load balancers and orchestrators, which do not
+// carry the API key, or opened in a browser, as the API docs are.
+var publicPaths = map[string]bool{
+    "/health":       true,
+    "/livez":        true,
+    "/readyz":       true,
+    "/openapi.json": true,
+    "/docs":         true,
+}
+
+// ownKeyPaths check a separate key themselves, so scrapers never need the
+// gateway key.
+var ownKeyPaths = map[string]bool{
+    "/metrics": true,
+}
+
+// apiKey is a key the gateway accepts. Its name is recorded as the actor
+// in audit entries, so each caller should be given its own key.
+type apiKey struct {
+    name  string
+    value string
+}
+
+// loadAPIKeys reads API_GATEWAY_KEY, named "gateway", and API_GATEWAY_KEYS,
+// a comma-separated list of name:key pairs. At least one key is required.
+func loadAPIKeys() []apiKey {
+    var keys []apiKey
+    if value := os.Getenv("API_GATEWAY_KEY"); value != "" {
+        keys = append(keys, apiKey{name: "gateway", value: value})
+    }
+    
+    for _, pair := range strings.Split(os.Getenv("API_GATEWAY_KEYS"), ",") {
+        pair = strings.TrimSpace(pair)
+        if pair == "" {
+            continue
+        }
+        name, value, ok := strings.Cut(pair, ":")
+        name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+        if !ok || name == "" || value == "" {
+            log.Fatal().Msg("API_GATEWAY_KEYS entries must be name:key")
+        }
+        keys = append(keys, apiKey{name: name, value: value})
+    }
     
-    if apiKey == "" {
-        log.Fatal().Msg("API_GATEWAY_KEY environment variable is not set")
+    if len(keys) == 0 {
+        log.Fatal().Msg("API_GATEWAY_KEY or API_GATEWAY_KEYS environment variable must be set")
+    }
+    return keys
+}
+
+// matchAPIKey returns the name of the key equal to requestKey. Every key
+// is compared, in constant time, so the timing does not reveal which one
+// nearly matched.
+func matchAPIKey(keys []apiKey, requestKey string) (string, bool) {
+    var name string
+    for _, key := range keys {
+        if subtle.ConstantTimeCompare([]byte(requestKey), []byte(key.value)) == 1 {
+            name = key.name
+        }
     }
+    return name, name != ""
+}
+
+func APIKeyAuthMiddleware() gin.HandlerFunc {
+    keys := loadAPIKeys()
     
     return func(c *gin.Context) {
-        if c.Request.URL.Path == "/health" {
+        if publicPaths[c.Request.URL.Path] || ownKeyPaths[c.Request.URL.Path] {
             c.Next()
             return
         }
         
         requestKey := c.GetHeader("X-Api-Key")
+        // EventSource cannot send custom headers, so the live stream
+        // also accepts the key as a query parameter.
+        if requestKey == "" && c.Request.URL.Path == "/live" {
+            requestKey = c.Query("api_key")
+        }
         if requestKey == "" {
-            log.Warn().
+            zerolog.Ctx(c.Request.Context()).Warn().
                 Str("path", c.Request.URL.Path).
                 Str("method", c.Request.Method).
                 Msg("Missing API key")
             
-            c.JSON(http.StatusUnauthorized, gin.H{
-                "status":  "ERROR",
-                "code":    "MISSING_API_KEY",
-                "message": "API key is required",
-            })
+            utils.HandleErrorResponse(c, utils.NewUnauthorizedError("MISSING_API_KEY", "API key is required", nil), utils.GetRequestID(c))
             c.Abort()
             return
         }
         
-        if requestKey != apiKey {
-            log.Warn().
+        name, ok := matchAPIKey(keys, requestKey)
+        if !ok {
+            zerolog.Ctx(c.Request.Context()).Warn().
                 Str("path", c.Request.URL.Path).
                 Str("method", c.Request.Method).
                 Msg("Invalid API key")
             
-            c.JSON(http.StatusForbidden, gin.H{
-                "status":  "ERROR",
-                "code":    "INVALID_API_KEY",
-                "message": "Invalid API key",
-            })
+            utils.HandleErrorResponse(c, utils.NewForbiddenError("INVALID_API_KEY", "Invalid API key", nil), utils.GetRequestID(c))
             c.Abort()
             return
         }
         
+        c.Set(utils.APIKeyNameContextKey, name)
         c.Next()
     }
 }
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	AuditActionRegistrationCreate      = "registration.create"
+	AuditActionRegistrationBatchCreate = "registration.batch_create"
+	AuditActionRegistrationImport      = "registration.import"
+	AuditActionReferralCodeCreate      = "referral_code.create"
+	AuditActionInviteCodeCreate        = "invite_code.create"
+	AuditActionExportCSV               = "export.csv"
+	AuditActionExportStats             = "export.stats"
+	AuditActionExportAttribution       = "export.attribution"
+	AuditActionDataSubjectExport       = "data_subject.export"
+	AuditActionDataSubjectErase        = "data_subject.erase"
+	AuditActionRetentionPurge          = "retention.purge"
+	AuditActionRetentionPreview        = "retention.preview"
+	AuditActionAuditLogRead            = "audit_log.read"
+)
+
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditLog records who did what to which entity. Changes holds the diff
+// of mutated fields and Metadata any other context (row counts, IDs).
+// Neither should contain personal data such as emails, phone numbers or
+// free text a client supplied (an uploaded file's name may be a person's
+// name), so that entries never need to be rewritten. IPAddress holds
+// only the client's network (/24 or /48), never the full address.
+type AuditLog struct {
+	ID         int64                  `json:"id" db:"id"`
+	Actor      string                 `json:"actor" db:"actor"`
+	Action     string                 `json:"action" db:"action"`
+	EntityType string                 `json:"entity_type" db:"entity_type"`
+	EntityID   string                 `json:"entity_id" db:"entity_id"`
+	RequestID  string                 `json:"request_id" db:"request_id"`
+	IPAddress  string                 `json:"ip_address" db:"ip_address"`
+	Changes    map[string]FieldChange `json:"changes" db:"changes"`
+	Metadata   map[string]interface{} `json:"metadata" db:"metadata"`
+	CreatedOn  time.Time              `json:"created_on" db:"created_on"`
+}
+
+type AuditLogFilter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// DataSubjectRecord is everything stored about one person, found by their
+// email or phone number.
+type DataSubjectRecord struct {
+	Registrations   []Registration
+	Groups          []RegistrationGroup
+	AuditLogs       []AuditLog
+	CachedResponses []IdempotencyRecord
+}
+
+type ErasureResult struct {
+	RegistrationIDs       []int
+	PurgedCachedResponses int64
+	ErasedOn              time.Time
+}
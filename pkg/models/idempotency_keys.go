@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+type IdempotencyRecord struct {
+	Key          string     `json:"idempotency_key" db:"idempotency_key"`
+	Method       string     `json:"method" db:"method"`
+	Path         string     `json:"path" db:"path"`
+	RequestHash  string     `json:"request_hash" db:"request_hash"`
+	StatusCode   *int       `json:"status_code" db:"status_code"`
+	ContentType  string     `json:"content_type" db:"content_type"`
+	ResponseBody []byte     `json:"response_body" db:"response_body"`
+	CreatedOn    time.Time  `json:"created_on" db:"created_on"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at"`
+	LockedUntil  *time.Time `json:"locked_until" db:"locked_until"`
+}
+
+func (r *IdempotencyRecord) IsComplete() bool {
+	return r.StatusCode != nil
+}
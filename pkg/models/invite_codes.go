@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+type InviteCode struct {
+	ID        int        `json:"id" db:"id"`
+	Code      string     `json:"code" db:"code"`
+	Label     string     `json:"label" db:"label"`
+	MaxUses   *int       `json:"max_uses" db:"max_uses"`
+	UsedCount int        `json:"used_count" db:"used_count"`
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
+	Active    bool       `json:"active" db:"active"`
+	CreatedOn time.Time  `json:"created_on" db:"created_on"`
+}
+
+func (ic *InviteCode) IsExpired(now time.Time) bool {
+	return ic.ExpiresAt != nil && !now.Before(*ic.ExpiresAt)
+}
+
+func (ic *InviteCode) IsExhausted() bool {
+	return ic.MaxUses != nil && ic.UsedCount >= *ic.MaxUses
+}
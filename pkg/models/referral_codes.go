@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+type ReferralCode struct {
+	ID          int       `json:"id" db:"id"`
+	Code        string    `json:"code" db:"code"`
+	Owner       string    `json:"owner" db:"owner"`
+	Description string    `json:"description" db:"description"`
+	Active      bool      `json:"active" db:"active"`
+	CreatedOn   time.Time `json:"created_on" db:"created_on"`
+}
+
+type AttributionReport struct {
+	Total          int
+	BySource       []FieldCount
+	ByMedium       []FieldCount
+	ByCampaign     []FieldCount
+	ByReferralCode []FieldCount
+}
@@ -0,0 +1,12 @@
+package models
+
+import (
+	"time"
+)
+
+type RegistrationGroup struct {
+	ID                    int       `json:"id" db:"id"`
+	Name                  string    `json:"name" db:"name"`
+	PrimaryRegistrationID *int      `json:"primary_registration_id" db:"primary_registration_id"`
+	CreatedOn             time.Time `json:"created_on" db:"created_on"`
+}
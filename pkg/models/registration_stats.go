@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+type FieldCount struct {
+	Value string `json:"value" db:"value"`
+	Count int    `json:"count" db:"count"`
+}
+
+type PeriodCount struct {
+	Period time.Time `json:"period" db:"period"`
+	Count  int       `json:"count" db:"count"`
+}
+
+type RegistrationStats struct {
+	Total       int
+	ByFoodPref  []FieldCount
+	ByTShirt    []FieldCount
+	ByMktSource []FieldCount
+	ByOrgName   []FieldCount
+	PerDay      []PeriodCount
+	PerHour     []PeriodCount
+}
@@ -5,14 +5,24 @@ import (
 )
 
 type Registration struct {
-	ID          int       `json:"id" db:"id"`
-	FullName    string    `json:"full_name" db:"full_name"`
-	Email       string    `json:"email" db:"email"`
-	Phone       string    `json:"phone" db:"phone"`
-	OrgName     string    `json:"org_name" db:"org_name"`
-	Designation string    `json:"designation" db:"designation"`
-	MktSource   string    `json:"mkt_source" db:"mkt_source"`
-	FoodPref    string    `json:"food_pref" db:"food_pref"`
-	TShirt      string    `json:"t_shirt" db:"t_shirt"`
-	CreatedOn   time.Time `json:"created_on" db:"created_on"`
+	ID           int        `json:"id" db:"id"`
+	FullName     string     `json:"full_name" db:"full_name"`
+	Email        string     `json:"email" db:"email"`
+	Phone        string     `json:"phone" db:"phone"`
+	OrgName      string     `json:"org_name" db:"org_name"`
+	Designation  string     `json:"designation" db:"designation"`
+	MktSource    string     `json:"mkt_source" db:"mkt_source"`
+	FoodPref     string     `json:"food_pref" db:"food_pref"`
+	TShirt       string     `json:"t_shirt" db:"t_shirt"`
+	UTMSource    string     `json:"utm_source" db:"utm_source"`
+	UTMMedium    string     `json:"utm_medium" db:"utm_medium"`
+	UTMCampaign  string     `json:"utm_campaign" db:"utm_campaign"`
+	UTMTerm      string     `json:"utm_term" db:"utm_term"`
+	UTMContent   string     `json:"utm_content" db:"utm_content"`
+	Referrer     string     `json:"referrer" db:"referrer"`
+	ReferralCode string     `json:"referral_code" db:"referral_code"`
+	InviteCode   string     `json:"invite_code" db:"invite_code"`
+	GroupID      *int       `json:"group_id" db:"group_id"`
+	ErasedOn     *time.Time `json:"erased_on" db:"erased_on"`
+	CreatedOn    time.Time  `json:"created_on" db:"created_on"`
 }
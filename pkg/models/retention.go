@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	RetentionActionAnonymize = "anonymize"
+	RetentionActionDelete    = "delete"
+)
+
+// RetentionPolicy decides when registration data is purged. With an event
+// end date every registration becomes due RetentionDays after the event
+// ends; without one each registration is due RetentionDays after it was
+// created.
+type RetentionPolicy struct {
+	Enabled       bool
+	EventEndDate  *time.Time
+	RetentionDays int
+	Action        string
+}
+
+// Cutoff returns the creation time before which registrations are due for
+// purging, and false when nothing is due yet. Without an event end date a
+// retention period of zero or less is never due.
+func (p RetentionPolicy) Cutoff(now time.Time) (time.Time, bool) {
+	if !p.Enabled || (p.EventEndDate == nil && p.RetentionDays <= 0) {
+		return time.Time{}, false
+	}
+
+	retention := time.Duration(p.RetentionDays) * 24 * time.Hour
+	if p.EventEndDate != nil {
+		if now.Before(p.EventEndDate.Add(retention)) {
+			return time.Time{}, false
+		}
+		return now, true
+	}
+
+	return now.Add(-retention), true
+}
+
+type RetentionPreview struct {
+	RegistrationIDs []int
+	OldestCreatedOn *time.Time
+	NewestCreatedOn *time.Time
+}
+
+type RetentionPurgeResult struct {
+	Action                string
+	Cutoff                time.Time
+	RegistrationIDs       []int
+	PurgedCachedResponses int64
+}
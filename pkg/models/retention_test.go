@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionCutoff(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	eventEnd := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		cutoff time.Time
+		due    bool
+	}{
+		{
+			name:   "disabled",
+			policy: RetentionPolicy{RetentionDays: 30},
+		},
+		{
+			name:   "rolling window",
+			policy: RetentionPolicy{Enabled: true, RetentionDays: 30},
+			cutoff: now.AddDate(0, 0, -30),
+			due:    true,
+		},
+		{
+			name:   "zero days without an event end date",
+			policy: RetentionPolicy{Enabled: true},
+		},
+		{
+			name:   "before the event end date plus retention",
+			policy: RetentionPolicy{Enabled: true, RetentionDays: 30, EventEndDate: &eventEnd},
+		},
+		{
+			name:   "after the event end date plus retention",
+			policy: RetentionPolicy{Enabled: true, RetentionDays: 10, EventEndDate: &eventEnd},
+			cutoff: now,
+			due:    true,
+		},
+		{
+			name:   "zero days after the event end date",
+			policy: RetentionPolicy{Enabled: true, EventEndDate: &eventEnd},
+			cutoff: now,
+			due:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cutoff, due := tt.policy.Cutoff(now)
+			if due != tt.due || !cutoff.Equal(tt.cutoff) {
+				t.Errorf("Cutoff = %v, %v; want %v, %v", cutoff, due, tt.cutoff, tt.due)
+			}
+		})
+	}
+}
+
+func TestRetentionCutoffIsDueExactlyAtTheBoundary(t *testing.T) {
+	eventEnd := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	policy := RetentionPolicy{Enabled: true, RetentionDays: 14, EventEndDate: &eventEnd}
+	boundary := eventEnd.AddDate(0, 0, 14)
+
+	if _, due := policy.Cutoff(boundary.Add(-time.Second)); due {
+		t.Error("due a second before the retention period ends")
+	}
+	if _, due := policy.Cutoff(boundary); !due {
+		t.Error("not due when the retention period ends")
+	}
+}
@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+// swaggerUIVersion pins the swagger-ui-dist release loaded by /docs.
+const swaggerUIVersion = "5.17.14"
+
+// UndocumentedRoutes are served but deliberately left out of the
+// specification: the specification itself, its viewer and the Prometheus
+// scrape endpoint.
+var UndocumentedRoutes = map[string]bool{
+	"GET /openapi.json": true,
+	"GET /docs":         true,
+	"GET /metrics":      true,
+}
+
+// Spec is the embedded OpenAPI document, validated and ready to route
+// requests against.
+type Spec struct {
+	doc    *openapi3.T
+	router routers.Router
+	json   []byte
+}
+
+// Load parses and validates the embedded document.
+func Load(ctx context.Context) (*Spec, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("parse openapi.yaml: %w", err)
+	}
+	if err := doc.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("validate openapi.yaml: %w", err)
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("route openapi.yaml: %w", err)
+	}
+
+	body, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("encode openapi.yaml: %w", err)
+	}
+
+	return &Spec{doc: doc, router: router, json: body}, nil
+}
+
+func (s *Spec) Document() *openapi3.T {
+	return s.doc
+}
+
+// Handler serves the document as JSON at /openapi.json.
+func (s *Spec) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", s.json)
+	}
+}
+
+// DocsHandler serves a Swagger UI page for /openapi.json.
+func (s *Spec) DocsHandler() gin.HandlerFunc {
+	page := []byte(fmt.Sprintf(swaggerUIPage, swaggerUIVersion, swaggerUIVersion))
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
+
+var ginParamPattern = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// CheckRoutes compares the router with the document and describes every
+// route without an operation and every operation without a route.
+func (s *Spec) CheckRoutes(routes gin.RoutesInfo) []string {
+	served := make(map[string]bool, len(routes))
+	var problems []string
+
+	for _, route := range routes {
+		key := route.Method + " " + ginParamPattern.ReplaceAllString(route.Path, "{$1}")
+		served[key] = true
+		if UndocumentedRoutes[key] {
+			continue
+		}
+		if item := s.doc.Paths.Find(strings.SplitN(key, " ", 2)[1]); item == nil || item.GetOperation(route.Method) == nil {
+			problems = append(problems, "route "+key+" has no operation in openapi.yaml")
+		}
+	}
+
+	for path, item := range s.doc.Paths.Map() {
+		for method := range item.Operations() {
+			if key := method + " " + path; !served[key] {
+				problems = append(problems, "operation "+key+" in openapi.yaml has no route")
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>TX QR Tool API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@%s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@%s/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
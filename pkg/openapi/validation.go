@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+// ValidationMiddleware rejects requests whose parameters or body do not
+// match the document, with the same VALIDATION_ERROR envelope the handlers
+// use. Requests for paths the document does not describe pass through, as
+// do multipart uploads, which the import handler checks itself. Responses
+// are not validated.
+func (s *Spec) ValidationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route, pathParams, err := s.router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		options := &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			ExcludeRequestBody: strings.HasPrefix(c.ContentType(), "multipart/"),
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			utils.HandleErrorResponse(c, requestError(err), utils.GetRequestID(c))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requestError turns a validation failure into the error the handler would
+// have returned for the same problem.
+func requestError(err error) *utils.AppError {
+	var requestErrs []*openapi3filter.RequestError
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		for _, e := range multi {
+			var requestErr *openapi3filter.RequestError
+			if errors.As(e, &requestErr) {
+				requestErrs = append(requestErrs, requestErr)
+			}
+		}
+	} else {
+		var requestErr *openapi3filter.RequestError
+		if errors.As(err, &requestErr) {
+			requestErrs = append(requestErrs, requestErr)
+		}
+	}
+
+	// A body that cannot be read (e.g. past the body limit) or decoded
+	// never reached the schema; report it as the handlers do.
+	for _, requestErr := range requestErrs {
+		if requestErr.RequestBody == nil {
+			continue
+		}
+		if requestErr.Reason == "reading failed" {
+			return utils.NewBadRequestError("INVALID_BODY", "Failed to read request body", requestErr)
+		}
+		var parseErr *openapi3filter.ParseError
+		if errors.As(requestErr, &parseErr) {
+			return utils.NewBadRequestError("INVALID_JSON", "Invalid JSON format", requestErr)
+		}
+	}
+
+	var validationErrors []utils.ValidationError
+	for _, requestErr := range requestErrs {
+		validationErrors = append(validationErrors, fieldErrors(requestErr)...)
+	}
+	if len(validationErrors) == 0 {
+		validationErrors = []utils.ValidationError{{Message: err.Error()}}
+	}
+
+	return &utils.AppError{
+		HTTPCode:         400,
+		Code:             "VALIDATION_ERROR",
+		Message:          "Request does not match the API specification",
+		Err:              err,
+		ValidationErrors: validationErrors,
+	}
+}
+
+func fieldErrors(requestErr *openapi3filter.RequestError) []utils.ValidationError {
+	var field string
+	if requestErr.Parameter != nil {
+		field = requestErr.Parameter.Name
+	}
+
+	var schemaErrs []*openapi3.SchemaError
+	var multi openapi3.MultiError
+	var schemaErr *openapi3.SchemaError
+	switch {
+	case errors.As(requestErr.Err, &multi):
+		for _, e := range multi {
+			if errors.As(e, &schemaErr) {
+				schemaErrs = append(schemaErrs, schemaErr)
+			}
+		}
+	case errors.As(requestErr.Err, &schemaErr):
+		schemaErrs = append(schemaErrs, schemaErr)
+	}
+
+	if len(schemaErrs) == 0 {
+		message := requestErr.Reason
+		if message == "" && requestErr.Err != nil {
+			message = requestErr.Err.Error()
+		}
+		return []utils.ValidationError{{Field: field, Message: message}}
+	}
+
+	validationErrors := make([]utils.ValidationError, 0, len(schemaErrs))
+	for _, schemaErr := range schemaErrs {
+		name := field
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			name = fieldPath(pointer)
+		}
+		validationErrors = append(validationErrors, utils.ValidationError{Field: name, Message: schemaErr.Reason})
+	}
+	return validationErrors
+}
+
+// fieldPath renders a JSON pointer the way handler validation names
+// fields, e.g. registrations[0].email.
+func fieldPath(pointer []string) string {
+	var b strings.Builder
+	for _, segment := range pointer {
+		if _, err := strconv.Atoi(segment); err == nil {
+			b.WriteString("[" + segment + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(segment)
+	}
+	return b.String()
+}
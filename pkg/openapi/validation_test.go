@@ -0,0 +1,117 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+func TestFieldPath(t *testing.T) {
+	tests := map[string][]string{
+		"email":                  {"email"},
+		"registrations[0].phone": {"registrations", "0", "phone"},
+		"registrations[12]":      {"registrations", "12"},
+		"[3].name":               {"3", "name"},
+	}
+	for want, pointer := range tests {
+		if got := fieldPath(pointer); got != want {
+			t.Errorf("fieldPath(%q) = %q, want %q", pointer, got, want)
+		}
+	}
+}
+
+type errorBody struct {
+	Code   string                  `json:"code"`
+	Errors []utils.ValidationError `json:"errors"`
+}
+
+func validate(t *testing.T, method, target, body string) (int, errorBody) {
+	t.Helper()
+
+	spec, err := Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(spec.ValidationMiddleware())
+	router.Any("/*path", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var decoded errorBody
+	if w.Code != http.StatusNoContent {
+		if err := json.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("decode %s: %v", w.Body, err)
+		}
+	}
+	return w.Code, decoded
+}
+
+func fields(errs []utils.ValidationError) map[string]bool {
+	names := make(map[string]bool, len(errs))
+	for _, e := range errs {
+		names[e.Field] = true
+	}
+	return names
+}
+
+func TestValidationNamesNestedBodyFields(t *testing.T) {
+	status, body := validate(t, http.MethodPost, "/register/batch", `{
+		"group_name": "Team",
+		"registrations": [
+			{"full_name": "Ada", "email": "ada@example.com", "phone": "9876543210", "food_pref": "veg", "t_shirt": "M"},
+			{"full_name": "Bob", "email": "bob@example.com", "phone": "98", "food_pref": "veg", "t_shirt": "XS"}
+		]
+	}`)
+
+	if status != http.StatusBadRequest || body.Code != "VALIDATION_ERROR" {
+		t.Fatalf("got %d %s, want 400 VALIDATION_ERROR", status, body.Code)
+	}
+	got := fields(body.Errors)
+	for _, want := range []string{"registrations[1].phone", "registrations[1].t_shirt"} {
+		if !got[want] {
+			t.Errorf("errors %+v do not name %s", body.Errors, want)
+		}
+	}
+}
+
+func TestValidationNamesQueryParameters(t *testing.T) {
+	status, body := validate(t, http.MethodGet, "/audit-logs?limit=abc", "")
+
+	if status != http.StatusBadRequest || body.Code != "VALIDATION_ERROR" {
+		t.Fatalf("got %d %s, want 400 VALIDATION_ERROR", status, body.Code)
+	}
+	if !fields(body.Errors)["limit"] {
+		t.Errorf("errors %+v do not name limit", body.Errors)
+	}
+}
+
+func TestValidationReportsMalformedJSONAsTheHandlersDo(t *testing.T) {
+	status, body := validate(t, http.MethodPost, "/register", `{"full_name": `)
+
+	if status != http.StatusBadRequest || body.Code != "INVALID_JSON" {
+		t.Errorf("got %d %s, want 400 INVALID_JSON", status, body.Code)
+	}
+}
+
+func TestValidationPassesValidAndUndocumentedRequests(t *testing.T) {
+	if status, _ := validate(t, http.MethodPost, "/register", `{"full_name": "Ada", "email": "ada@example.com", "phone": "9876543210", "food_pref": "veg", "t_shirt": "M"}`); status != http.StatusNoContent {
+		t.Errorf("valid registration got %d", status)
+	}
+	if status, _ := validate(t, http.MethodGet, "/metrics", ""); status != http.StatusNoContent {
+		t.Errorf("undocumented route got %d", status)
+	}
+}
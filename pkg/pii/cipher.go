@@ -0,0 +1,243 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	FieldFullName = "full_name"
+	FieldEmail    = "email"
+	FieldPhone    = "phone"
+
+	// FieldResponseBody binds cached idempotent responses, which repeat
+	// the fields above.
+	FieldResponseBody = "response_body"
+
+	prefix  = "enc:v1:"
+	keySize = 32
+)
+
+// Cipher encrypts personal data with envelope encryption: every value gets
+// its own random data key, which is sealed with a key-encryption key
+// identified by a key ID. Ciphertexts look like
+//
+//	enc:v1:<key id>:<sealed data key>:<sealed value>
+//
+// so old key IDs stay readable after the active key is rotated. Values
+// without the prefix are legacy plaintext and are returned unchanged.
+//
+// BlindIndex gives a deterministic keyed hash for equality lookups on
+// encrypted columns.
+type Cipher struct {
+	keys        map[string][]byte
+	activeKeyID string
+	indexKey    []byte
+}
+
+// NewCipherFromEnv reads PII_ENCRYPTION_KEYS ("id:base64key,..."),
+// PII_ACTIVE_KEY_ID and PII_BLIND_INDEX_KEY. All keys are 32 bytes,
+// base64-encoded.
+func NewCipherFromEnv() (*Cipher, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(os.Getenv("PII_ENCRYPTION_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("PII_ENCRYPTION_KEYS entry %q must be id:base64key", entry)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("PII_ENCRYPTION_KEYS key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+
+	indexKey, err := decodeKey(os.Getenv("PII_BLIND_INDEX_KEY"))
+	if err != nil {
+		return nil, fmt.Errorf("PII_BLIND_INDEX_KEY: %w", err)
+	}
+
+	return NewCipher(keys, os.Getenv("PII_ACTIVE_KEY_ID"), indexKey)
+}
+
+func NewCipher(keys map[string][]byte, activeKeyID string, indexKey []byte) (*Cipher, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no PII encryption keys configured")
+	}
+	for id, key := range keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("key id %q must not contain ':'", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %q must be %d bytes", id, keySize)
+		}
+	}
+	if _, ok := keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("active key id %q is not configured", activeKeyID)
+	}
+	if len(indexKey) != keySize {
+		return nil, fmt.Errorf("blind index key must be %d bytes", keySize)
+	}
+
+	return &Cipher{keys: keys, activeKeyID: activeKeyID, indexKey: indexKey}, nil
+}
+
+// Encrypt seals value under the active key. The field name is bound to the
+// ciphertext as additional data, so a value cannot be moved to another
+// column and still decrypt.
+func (c *Cipher) Encrypt(field, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("generate data key: %w", err)
+	}
+
+	sealedKey, err := seal(c.keys[c.activeKeyID], dataKey, []byte(c.activeKeyID))
+	if err != nil {
+		return "", err
+	}
+	sealedValue, err := seal(dataKey, []byte(value), []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + c.activeKeyID + ":" +
+		base64.RawStdEncoding.EncodeToString(sealedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealedValue), nil
+}
+
+func (c *Cipher) Decrypt(field, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed ciphertext for %s", field)
+	}
+	keyID := parts[0]
+
+	kek, ok := c.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("unknown key id %q for %s", keyID, field)
+	}
+
+	sealedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("decode data key for %s: %w", field, err)
+	}
+	sealedValue, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("decode value for %s: %w", field, err)
+	}
+
+	dataKey, err := open(kek, sealedKey, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("unseal data key for %s: %w", field, err)
+	}
+	plaintext, err := open(dataKey, sealedValue, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("decrypt %s: %w", field, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Normalize returns the form of value that blind indexes compare: trimmed,
+// and lowercased for emails. Anything that checks for duplicates before
+// they reach the database must compare the same form.
+func Normalize(field, value string) string {
+	value = strings.TrimSpace(value)
+	if field == FieldEmail {
+		value = strings.ToLower(value)
+	}
+	return value
+}
+
+// BlindIndex returns a keyed hash of the normalised value, or "" for an
+// empty value.
+func (c *Cipher) BlindIndex(field, value string) string {
+	value = Normalize(field, value)
+	if value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(field + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NeedsRotation reports whether value is plaintext or sealed under a key
+// other than the active one.
+func (c *Cipher) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	keyID, _, _ := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	return keyID != c.activeKeyID
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("must decode to %d bytes, got %d", keySize, len(key))
+	}
+	return key, nil
+}
@@ -0,0 +1,105 @@
+package pii
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func newTestCipher(t *testing.T, keys map[string][]byte, activeKeyID string) *Cipher {
+	t.Helper()
+	c, err := NewCipher(keys, activeKeyID, testKey('i'))
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return c
+}
+
+func TestBlindIndexSurvivesKeyRotation(t *testing.T) {
+	before := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+	after := newTestCipher(t, map[string][]byte{"k1": testKey(1), "k2": testKey(2)}, "k2")
+
+	sealed, err := before.Encrypt(FieldEmail, "ada@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	// A row written before the rotation is still found by the lookup a
+	// request makes after it, and still decrypts.
+	if before.BlindIndex(FieldEmail, "ada@example.com") != after.BlindIndex(FieldEmail, "ada@example.com") {
+		t.Error("blind index changed when the active encryption key rotated")
+	}
+	opened, err := after.Decrypt(FieldEmail, sealed)
+	if err != nil || opened != "ada@example.com" {
+		t.Errorf("Decrypt after rotation = %q, %v", opened, err)
+	}
+	if !after.NeedsRotation(sealed) {
+		t.Error("value sealed under the old key does not need rotation")
+	}
+
+	resealed, err := after.Encrypt(FieldEmail, opened)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(resealed, prefix+"k2:") || after.NeedsRotation(resealed) {
+		t.Errorf("re-encrypted value %q is not under the active key", resealed)
+	}
+}
+
+func TestBlindIndexNormalisesLikeDuplicateChecks(t *testing.T) {
+	c := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+
+	if c.BlindIndex(FieldEmail, " Ada@Example.COM ") != c.BlindIndex(FieldEmail, "ada@example.com") {
+		t.Error("email blind index is case or space sensitive")
+	}
+	if c.BlindIndex(FieldPhone, " 9876543210 ") != c.BlindIndex(FieldPhone, "9876543210") {
+		t.Error("phone blind index is space sensitive")
+	}
+	if c.BlindIndex(FieldEmail, "ada@example.com") == c.BlindIndex(FieldPhone, "ada@example.com") {
+		t.Error("blind indexes of different fields collide")
+	}
+	if c.BlindIndex(FieldEmail, "  ") != "" {
+		t.Error("blank value has a blind index")
+	}
+}
+
+func TestBlindIndexDependsOnTheIndexKey(t *testing.T) {
+	c := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+	other, err := NewCipher(map[string][]byte{"k1": testKey(1)}, "k1", testKey('j'))
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+
+	if c.BlindIndex(FieldEmail, "ada@example.com") == other.BlindIndex(FieldEmail, "ada@example.com") {
+		t.Error("blind index does not depend on the index key")
+	}
+}
+
+func TestDecryptPassesLegacyPlaintextThrough(t *testing.T) {
+	c := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+
+	opened, err := c.Decrypt(FieldPhone, "9876543210")
+	if err != nil || opened != "9876543210" {
+		t.Errorf("Decrypt(plaintext) = %q, %v", opened, err)
+	}
+	if !c.NeedsRotation("9876543210") {
+		t.Error("plaintext value does not need rotation")
+	}
+}
+
+func TestDecryptFailsForARemovedKey(t *testing.T) {
+	before := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+	after := newTestCipher(t, map[string][]byte{"k2": testKey(2)}, "k2")
+
+	sealed, err := before.Encrypt(FieldFullName, "Ada")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if _, err := after.Decrypt(FieldFullName, sealed); err == nil {
+		t.Error("value sealed under a removed key decrypted")
+	}
+}
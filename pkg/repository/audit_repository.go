@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) (*models.AuditLog, error)
+	List(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLog, int, error)
+}
+
+type auditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *models.AuditLog) (*models.AuditLog, error) {
+	query := `
+        INSERT INTO audit_logs (actor, action, entity_type, entity_id, request_id, ip_address, changes, metadata)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id, created_on
+    `
+
+	if entry.Changes == nil {
+		entry.Changes = map[string]models.FieldChange{}
+	}
+	if entry.Metadata == nil {
+		entry.Metadata = map[string]interface{}{}
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, queryWrite)
+	defer cancel()
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		entry.Actor,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		entry.RequestID,
+		entry.IPAddress,
+		entry.Changes,
+		entry.Metadata,
+	).Scan(&entry.ID, &entry.CreatedOn)
+
+	if err != nil {
+		return nil, databaseError("Failed to write audit log", err)
+	}
+
+	return entry, nil
+}
+
+func (r *auditRepository) List(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLog, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Actor != "" {
+		addCondition("actor = $%d", filter.Actor)
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.EntityType != "" {
+		addCondition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		addCondition("entity_id = $%d", filter.EntityID)
+	}
+	if filter.RequestID != "" {
+		addCondition("request_id = $%d", filter.RequestID)
+	}
+	if filter.From != nil {
+		addCondition("created_on >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_on < $%d", *filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, queryReport)
+	defer cancel()
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM audit_logs `+where, args...).Scan(&total); err != nil {
+		return nil, 0, databaseError("Failed to count audit logs", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+        SELECT id, actor, action, entity_type, entity_id, request_id, ip_address, changes, metadata, created_on
+        FROM audit_logs
+        %s
+        ORDER BY created_on DESC, id DESC
+        LIMIT $%d OFFSET $%d
+    `, where, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, databaseError("Failed to fetch audit logs", err)
+	}
+	defer rows.Close()
+
+	var entries []models.AuditLog
+	for rows.Next() {
+		var entry models.AuditLog
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&entry.RequestID,
+			&entry.IPAddress,
+			&entry.Changes,
+			&entry.Metadata,
+			&entry.CreatedOn,
+		)
+		if err != nil {
+			return nil, 0, databaseError("Failed to scan audit log", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, databaseError("Error iterating audit logs", err)
+	}
+
+	return entries, total, nil
+}
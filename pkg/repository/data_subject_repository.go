@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pseudonymizeRegistrationsSQL replaces the personal fields of the given
+// registrations with placeholders derived from the row ID. Food, t-shirt,
+// organisation and attribution fields are kept so aggregate reports still
+// add up.
+const pseudonymizeRegistrationsSQL = `
+    UPDATE registrations
+    SET full_name = '[erased]',
+        email = 'erased+' || id || '@erased.invalid',
+        phone = 'E' || lpad(id::text, 12, '0'),
+        email_bidx = NULL,
+        phone_bidx = NULL,
+        designation = '',
+        referrer = '',
+        erased_on = NOW()
+    WHERE id = ANY($1) AND erased_on IS NULL
+    RETURNING id
+`
+
+// pseudonymizeGroupsSQL replaces the name of every group the given
+// registrations lead, or make up entirely, since a team is often named
+// after its leader. It must run before the registrations are erased or
+// deleted, while their group membership is still recorded.
+const pseudonymizeGroupsSQL = `
+    UPDATE registration_groups g
+    SET name = '[erased]'
+    WHERE g.primary_registration_id = ANY($1)
+       OR (g.id IN (SELECT group_id FROM registrations WHERE id = ANY($1))
+           AND NOT EXISTS (
+               SELECT 1 FROM registrations m
+               WHERE m.group_id = g.id AND m.erased_on IS NULL AND m.id <> ALL($1)
+           ))
+`
+
+// cachedResponsesMatchSQL matches stored idempotent responses that contain
+// any of the subject's emails or phones: by blind index ($1), or by
+// searching the body ($2) of responses cached before they were encrypted.
+const cachedResponsesMatchSQL = `
+    subject_bidx && $1::varchar[]
+    OR (subject_bidx IS NULL AND EXISTS (
+        SELECT 1 FROM unnest($2::text[]) AS term
+        WHERE position(convert_to(term, 'UTF8') IN response_body) > 0
+    ))
+`
+
+// purgeCachedResponsesSQL removes the subject's stored idempotent
+// responses; replaying them would hand the data back out.
+const purgeCachedResponsesSQL = `
+    DELETE FROM idempotency_keys
+    WHERE ` + cachedResponsesMatchSQL
+
+type DataSubjectRepository interface {
+	Find(ctx context.Context, email, phone string) (*models.DataSubjectRecord, error)
+	Erase(ctx context.Context, email, phone string) (*models.ErasureResult, error)
+}
+
+type dataSubjectRepository struct {
+	db     *pgxpool.Pool
+	cipher *pii.Cipher
+}
+
+func NewDataSubjectRepository(db *pgxpool.Pool, cipher *pii.Cipher) DataSubjectRepository {
+	return &dataSubjectRepository{db: db, cipher: cipher}
+}
+
+func (r *dataSubjectRepository) Find(ctx context.Context, email, phone string) (*models.DataSubjectRecord, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryReport)
+	defer cancel()
+	record := &models.DataSubjectRecord{}
+
+	registrations, err := findSubjectRegistrations(ctx, r.db, r.cipher, email, phone)
+	if err != nil {
+		return nil, err
+	}
+	record.Registrations = registrations
+	if len(registrations) == 0 {
+		return record, nil
+	}
+
+	ids, entityIDs, terms := subjectKeys(registrations)
+
+	rows, err := r.db.Query(ctx, `
+        SELECT id, name, primary_registration_id, created_on
+        FROM registration_groups
+        WHERE primary_registration_id = ANY($1)
+           OR id IN (SELECT group_id FROM registrations WHERE id = ANY($1))
+        ORDER BY id
+    `, ids)
+	if err != nil {
+		return nil, databaseError("Failed to fetch registration groups", err)
+	}
+	record.Groups, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.RegistrationGroup, error) {
+		var group models.RegistrationGroup
+		err := row.Scan(&group.ID, &group.Name, &group.PrimaryRegistrationID, &group.CreatedOn)
+		return group, err
+	})
+	if err != nil {
+		return nil, databaseError("Failed to scan registration groups", err)
+	}
+	groupIDs := make([]string, 0, len(record.Groups))
+	for _, group := range record.Groups {
+		groupIDs = append(groupIDs, strconv.Itoa(group.ID))
+	}
+
+	// Entries name a registration directly, name the group it was
+	// created in, or (imports, erasures, retention purges) list it in
+	// metadata.registration_ids.
+	rows, err = r.db.Query(ctx, `
+        SELECT id, actor, action, entity_type, entity_id, request_id, ip_address, changes, metadata, created_on
+        FROM audit_logs
+        WHERE (entity_type = 'registration' AND entity_id = ANY($1))
+           OR (entity_type = 'registration_group' AND entity_id = ANY($2))
+           OR EXISTS (
+               SELECT 1 FROM unnest($3::int[]) AS registration_id
+               WHERE metadata->'registration_ids' @> to_jsonb(registration_id)
+           )
+        ORDER BY created_on
+    `, entityIDs, groupIDs, ids)
+	if err != nil {
+		return nil, databaseError("Failed to fetch audit logs", err)
+	}
+	record.AuditLogs, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.AuditLog, error) {
+		var entry models.AuditLog
+		err := row.Scan(
+			&entry.ID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&entry.RequestID,
+			&entry.IPAddress,
+			&entry.Changes,
+			&entry.Metadata,
+			&entry.CreatedOn,
+		)
+		return entry, err
+	})
+	if err != nil {
+		return nil, databaseError("Failed to scan audit logs", err)
+	}
+
+	cachedQuery := `
+        SELECT idempotency_key, method, path, created_on, expires_at
+        FROM idempotency_keys
+        WHERE ` + cachedResponsesMatchSQL + `
+        ORDER BY created_on
+    `
+	rows, err = r.db.Query(ctx, cachedQuery, subjectIndexes(r.cipher, registrations), terms)
+	if err != nil {
+		return nil, databaseError("Failed to fetch cached responses", err)
+	}
+	record.CachedResponses, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.IdempotencyRecord, error) {
+		var cached models.IdempotencyRecord
+		err := row.Scan(&cached.Key, &cached.Method, &cached.Path, &cached.CreatedOn, &cached.ExpiresAt)
+		return cached, err
+	})
+	if err != nil {
+		return nil, databaseError("Failed to scan cached responses", err)
+	}
+
+	return record, nil
+}
+
+func (r *dataSubjectRepository) Erase(ctx context.Context, email, phone string) (*models.ErasureResult, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryReport)
+	defer cancel()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, databaseError("Failed to start transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	registrations, err := findSubjectRegistrations(ctx, tx, r.cipher, email, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ErasureResult{RegistrationIDs: []int{}}
+	if len(registrations) == 0 {
+		return result, nil
+	}
+
+	ids, _, terms := subjectKeys(registrations)
+
+	tag, err := tx.Exec(ctx, purgeCachedResponsesSQL, subjectIndexes(r.cipher, registrations), terms)
+	if err != nil {
+		return nil, databaseError("Failed to purge cached responses", err)
+	}
+	result.PurgedCachedResponses = tag.RowsAffected()
+
+	if _, err := tx.Exec(ctx, pseudonymizeGroupsSQL, ids); err != nil {
+		return nil, databaseError("Failed to erase registration groups", err)
+	}
+
+	rows, err := tx.Query(ctx, pseudonymizeRegistrationsSQL, ids)
+	if err != nil {
+		return nil, databaseError("Failed to erase registrations", err)
+	}
+	result.RegistrationIDs, err = pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, databaseError("Failed to erase registrations", err)
+	}
+
+	if err := tx.QueryRow(ctx, `SELECT NOW()`).Scan(&result.ErasedOn); err != nil {
+		return nil, databaseError("Failed to read erasure time", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, databaseError("Failed to commit erasure", err)
+	}
+
+	return result, nil
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// findSubjectRegistrations matches on the blind indexes, falling back to
+// the plaintext columns for rows not yet encrypted.
+func findSubjectRegistrations(ctx context.Context, q querier, cipher *pii.Cipher, email, phone string) ([]models.Registration, error) {
+	query := `
+        SELECT ` + registrationColumns + `
+        FROM registrations
+        WHERE erased_on IS NULL
+          AND (($1 <> '' AND (email_bidx = $1 OR (email_bidx IS NULL AND email = $3)))
+            OR ($2 <> '' AND (phone_bidx = $2 OR (phone_bidx IS NULL AND phone = $4))))
+        ORDER BY created_on
+    `
+
+	emailBidx := cipher.BlindIndex(pii.FieldEmail, email)
+	phoneBidx := cipher.BlindIndex(pii.FieldPhone, phone)
+	rows, err := q.Query(ctx, query, emailBidx, phoneBidx, email, phone)
+	if err != nil {
+		return nil, databaseError("Failed to fetch registrations", err)
+	}
+
+	registrations, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Registration, error) {
+		var reg models.Registration
+		err := scanRegistration(cipher, row, &reg)
+		return reg, err
+	})
+	if err != nil {
+		return nil, databaseError("Failed to scan registration", err)
+	}
+
+	return registrations, nil
+}
+
+// subjectKeys returns the registration IDs (as ints and as audit entity
+// IDs) and every email and phone on those rows, so derived data can be
+// matched even when the request named only one of them.
+func subjectKeys(registrations []models.Registration) ([]int, []string, []string) {
+	ids := make([]int, 0, len(registrations))
+	entityIDs := make([]string, 0, len(registrations))
+	terms := make([]string, 0, 2*len(registrations))
+	for _, reg := range registrations {
+		ids = append(ids, reg.ID)
+		entityIDs = append(entityIDs, strconv.Itoa(reg.ID))
+		terms = append(terms, reg.Email, reg.Phone)
+	}
+	return ids, entityIDs, terms
+}
+
+// subjectIndexes returns the blind indexes of every email and phone on the
+// registrations, for matching encrypted cached responses.
+func subjectIndexes(cipher *pii.Cipher, registrations []models.Registration) []string {
+	indexes := make([]string, 0, 2*len(registrations))
+	for _, reg := range registrations {
+		if index := cipher.BlindIndex(pii.FieldEmail, reg.Email); index != "" {
+			indexes = append(indexes, index)
+		}
+		if index := cipher.BlindIndex(pii.FieldPhone, reg.Phone); index != "" {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
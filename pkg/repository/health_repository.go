@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchemaVersion is the latest migration in supabase/ that this binary
+// relies on. Bump it with every new migration.
+const SchemaVersion = 15
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (version int64, dirty bool, err error)
+}
+
+type healthRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewHealthRepository(db *pgxpool.Pool) HealthRepository {
+	return &healthRepository{db: db}
+}
+
+func (r *healthRepository) Ping(ctx context.Context) error {
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
+
+	return r.db.Ping(ctx)
+}
+
+// SchemaVersion reads the version recorded by golang-migrate. A database
+// that has never been migrated reports version 0.
+func (r *healthRepository) SchemaVersion(ctx context.Context) (int64, bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
+
+	var version int64
+	var dirty bool
+	err := r.db.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
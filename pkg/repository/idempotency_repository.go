@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *models.IdempotencyRecord, ttl, lease time.Duration) (*models.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+	Release(ctx context.Context, record *models.IdempotencyRecord) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// idempotencyRepository stores replayable responses encrypted, since they
+// carry the registrant's name, email and phone. subject_bidx holds the
+// blind indexes of the emails and phones in each response so data-subject
+// requests can find them without decrypting every row.
+type idempotencyRepository struct {
+	db     *pgxpool.Pool
+	cipher *pii.Cipher
+}
+
+func NewIdempotencyRepository(db *pgxpool.Pool, cipher *pii.Cipher) IdempotencyRepository {
+	return &idempotencyRepository{db: db, cipher: cipher}
+}
+
+// reserveAttempts bounds how often Reserve retries when the key it
+// conflicted with disappears before it can be read.
+const reserveAttempts = 3
+
+// Reserve claims the key for a new request for the length of lease. It
+// takes over a key that has expired, or one left in progress past its
+// lease by a request with the same body. When the key is already held it
+// returns the stored record and false, so the caller can replay the
+// original response or report a conflict.
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyRecord, ttl, lease time.Duration) (*models.IdempotencyRecord, bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryWrite)
+	defer cancel()
+
+	// The key can be released or expire between the conflicting insert
+	// and the read; when that happens the insert is simply tried again.
+	for attempt := 0; attempt < reserveAttempts; attempt++ {
+		err := r.db.QueryRow(ctx, `
+            INSERT INTO idempotency_keys (idempotency_key, method, path, request_hash, expires_at, locked_until)
+            VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5), NOW() + make_interval(secs => $6))
+            ON CONFLICT (idempotency_key, method, path) DO UPDATE
+            SET request_hash = EXCLUDED.request_hash,
+                status_code = NULL,
+                content_type = NULL,
+                response_body = NULL,
+                subject_bidx = NULL,
+                created_on = NOW(),
+                expires_at = EXCLUDED.expires_at,
+                locked_until = EXCLUDED.locked_until
+            WHERE idempotency_keys.expires_at <= NOW()
+               OR (idempotency_keys.status_code IS NULL
+                   AND idempotency_keys.locked_until <= NOW()
+                   AND idempotency_keys.request_hash = EXCLUDED.request_hash)
+            RETURNING created_on, expires_at, locked_until
+        `, record.Key, record.Method, record.Path, record.RequestHash, ttl.Seconds(), lease.Seconds()).Scan(&record.CreatedOn, &record.ExpiresAt, &record.LockedUntil)
+
+		if err == nil {
+			return record, true, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, databaseError("Failed to reserve idempotency key", err)
+		}
+
+		var existing models.IdempotencyRecord
+		err = r.db.QueryRow(ctx, `
+            SELECT idempotency_key, method, path, request_hash, status_code, content_type, response_body, created_on, expires_at, locked_until
+            FROM idempotency_keys
+            WHERE idempotency_key = $1 AND method = $2 AND path = $3 AND expires_at > NOW()
+        `, record.Key, record.Method, record.Path).Scan(
+			&existing.Key,
+			&existing.Method,
+			&existing.Path,
+			&existing.RequestHash,
+			&existing.StatusCode,
+			&existing.ContentType,
+			&existing.ResponseBody,
+			&existing.CreatedOn,
+			&existing.ExpiresAt,
+			&existing.LockedUntil,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, false, databaseError("Failed to fetch idempotency key", err)
+		}
+
+		// Responses cached before encryption was enabled are plaintext
+		// and pass through unchanged.
+		if existing.ResponseBody != nil {
+			body, err := r.cipher.Decrypt(pii.FieldResponseBody, string(existing.ResponseBody))
+			if err != nil {
+				return nil, false, utils.NewInternalServerError("ENCRYPTION_ERROR", "Failed to decrypt idempotent response", err)
+			}
+			existing.ResponseBody = []byte(body)
+		}
+
+		return &existing, false, nil
+	}
+
+	return nil, false, utils.NewConflictError("IDEMPOTENCY_REQUEST_IN_PROGRESS", "A request with this Idempotency-Key is still being processed", nil)
+}
+
+// Complete stores the response for a key reserved by Reserve. Complete and
+// Release only touch the key while it still holds this request's lease, so
+// a request that outlived its lease cannot overwrite or drop the key for
+// the retry that took it over.
+func (r *idempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	sealed, err := r.cipher.Encrypt(pii.FieldResponseBody, string(record.ResponseBody))
+	if err != nil {
+		return utils.NewInternalServerError("ENCRYPTION_ERROR", "Failed to encrypt idempotent response", err)
+	}
+
+	ctx, cancel := withQueryTimeout(ctx, queryWrite)
+	defer cancel()
+	_, err = r.db.Exec(ctx, `
+        UPDATE idempotency_keys
+        SET status_code = $4, content_type = $5, response_body = $6, subject_bidx = $7
+        WHERE idempotency_key = $1 AND method = $2 AND path = $3 AND locked_until = $8
+    `, record.Key, record.Method, record.Path, record.StatusCode, record.ContentType, []byte(sealed), responseSubjects(r.cipher, record.ResponseBody), record.LockedUntil)
+	if err != nil {
+		return databaseError("Failed to store idempotent response", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, record *models.IdempotencyRecord) error {
+	ctx, cancel := withQueryTimeout(ctx, queryWrite)
+	defer cancel()
+	_, err := r.db.Exec(ctx, `
+        DELETE FROM idempotency_keys
+        WHERE idempotency_key = $1 AND method = $2 AND path = $3 AND locked_until = $4
+    `, record.Key, record.Method, record.Path, record.LockedUntil)
+	if err != nil {
+		return databaseError("Failed to release idempotency key", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes keys past their expiry and returns how many were
+// deleted.
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryWrite)
+	defer cancel()
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, databaseError("Failed to delete expired idempotency keys", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// responseSubjects returns the blind indexes of every "email" and "phone"
+// value in a JSON response body, at any depth. It is never nil, so stored
+// rows can be told apart from those cached before responses were
+// encrypted, whose index is NULL.
+func responseSubjects(cipher *pii.Cipher, body []byte) []string {
+	indexes := []string{}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return indexes
+	}
+
+	seen := make(map[string]bool)
+	var walk func(value any)
+	walk = func(value any) {
+		switch value := value.(type) {
+		case map[string]any:
+			for key, child := range value {
+				if text, ok := child.(string); ok && (key == pii.FieldEmail || key == pii.FieldPhone) {
+					if index := cipher.BlindIndex(key, text); index != "" && !seen[index] {
+						seen[index] = true
+						indexes = append(indexes, index)
+					}
+					continue
+				}
+				walk(child)
+			}
+		case []any:
+			for _, child := range value {
+				walk(child)
+			}
+		}
+	}
+	walk(decoded)
+
+	sort.Strings(indexes)
+	return indexes
+}
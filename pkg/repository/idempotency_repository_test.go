@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
+)
+
+func testCipher(t *testing.T) *pii.Cipher {
+	t.Helper()
+	cipher, err := pii.NewCipher(map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}, "k1", bytes.Repeat([]byte{2}, 32))
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return cipher
+}
+
+func TestResponseSubjectsIndexesNestedEmailsAndPhones(t *testing.T) {
+	cipher := testCipher(t)
+	body := []byte(`{"data": {"results": [
+		{"registration": {"full_name": "Ada", "email": "Ada@Example.com", "phone": "9876543210"}},
+		{"registration": {"full_name": "Bob", "email": "bob@example.com", "phone": "9876543211"}},
+		{"registration": {"email": "ada@example.com", "phone": 42}}
+	]}}`)
+
+	got := make(map[string]bool)
+	for _, index := range responseSubjects(cipher, body) {
+		got[index] = true
+	}
+
+	want := []string{
+		cipher.BlindIndex(pii.FieldEmail, "ada@example.com"),
+		cipher.BlindIndex(pii.FieldPhone, "9876543210"),
+		cipher.BlindIndex(pii.FieldEmail, "bob@example.com"),
+		cipher.BlindIndex(pii.FieldPhone, "9876543211"),
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d indexes, want %d", len(got), len(want))
+	}
+	for _, index := range want {
+		if !got[index] {
+			t.Errorf("missing index %s", index)
+		}
+	}
+}
+
+func TestResponseSubjectsIsNeverNil(t *testing.T) {
+	cipher := testCipher(t)
+	for _, body := range []string{`{"status": "SUCCESS"}`, `not json`, ``} {
+		if got := responseSubjects(cipher, []byte(body)); got == nil || len(got) != 0 {
+			t.Errorf("responseSubjects(%q) = %v, want an empty slice", body, got)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type InviteCodeRepository interface {
+	Create(ctx context.Context, code *models.InviteCode) (*models.InviteCode, error)
+	GetAll(ctx context.Context) ([]models.InviteCode, error)
+	GetByCode(ctx context.Context, code string) (*models.InviteCode, error)
+	GetByCodes(ctx context.Context, codes []string) ([]models.InviteCode, error)
+}
+
+type inviteCodeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewInviteCodeRepository(db *pgxpool.Pool) InviteCodeRepository {
+	return &inviteCodeRepository{db: db}
+}
+
+const inviteCodeColumns = `id, code, label, max_uses, used_count, expires_at, active, created_on`
+
+func scanInviteCode(row pgx.Row, ic *models.InviteCode) error {
+	return row.Scan(
+		&ic.ID,
+		&ic.Code,
+		&ic.Label,
+		&ic.MaxUses,
+		&ic.UsedCount,
+		&ic.ExpiresAt,
+		&ic.Active,
+		&ic.CreatedOn,
+	)
+}
+
+func (r *inviteCodeRepository) Create(ctx context.Context, code *models.InviteCode) (*models.InviteCode, error) {
+	query := `
+        INSERT INTO invite_codes (code, label, max_uses, expires_at, active)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, used_count, created_on
+    `
+
+	ctx, cancel := withQueryTimeout(ctx, queryWrite)
+	defer cancel()
+	err := r.db.QueryRow(ctx, query, code.Code, code.Label, code.MaxUses, code.ExpiresAt, code.Active).
+		Scan(&code.ID, &code.UsedCount, &code.CreatedOn)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return nil, utils.NewDuplicateError("DUPLICATE_INVITE_CODE", "Invite code already exists", err)
+		}
+		return nil, databaseError("Failed to create invite code", err)
+	}
+
+	return code, nil
+}
+
+func (r *inviteCodeRepository) GetAll(ctx context.Context) ([]models.InviteCode, error) {
+	query := `
+        SELECT ` + inviteCodeColumns + `
+        FROM invite_codes
+        ORDER BY created_on DESC
+    `
+
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, databaseError("Failed to fetch invite codes", err)
+	}
+	defer rows.Close()
+
+	var codes []models.InviteCode
+	for rows.Next() {
+		var ic models.InviteCode
+		if err := scanInviteCode(rows, &ic); err != nil {
+			return nil, databaseError("Failed to scan invite code", err)
+		}
+		codes = append(codes, ic)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, databaseError("Error iterating invite codes", err)
+	}
+
+	return codes, nil
+}
+
+func (r *inviteCodeRepository) GetByCode(ctx context.Context, code string) (*models.InviteCode, error) {
+	query := `
+        SELECT ` + inviteCodeColumns + `
+        FROM invite_codes
+        WHERE code = $1
+    `
+
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
+	var ic models.InviteCode
+	err := scanInviteCode(r.db.QueryRow(ctx, query, code), &ic)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewNotFoundError("INVITE_CODE_NOT_FOUND", "Invite code not found", err)
+		}
+		return nil, databaseError("Failed to fetch invite code", err)
+	}
+
+	return &ic, nil
+}
+
+// GetByCodes returns the invite codes among codes that exist, in no
+// particular order.
+func (r *inviteCodeRepository) GetByCodes(ctx context.Context, codes []string) ([]models.InviteCode, error) {
+	query := `
+        SELECT ` + inviteCodeColumns + `
+        FROM invite_codes
+        WHERE code = ANY($1)
+    `
+
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
+	rows, err := r.db.Query(ctx, query, codes)
+	if err != nil {
+		return nil, databaseError("Failed to fetch invite codes", err)
+	}
+	found, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.InviteCode, error) {
+		var ic models.InviteCode
+		err := scanInviteCode(row, &ic)
+		return ic, err
+	})
+	if err != nil {
+		return nil, databaseError("Failed to scan invite code", err)
+	}
+
+	return found, nil
+}
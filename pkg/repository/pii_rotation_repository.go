@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PIIRotationRepository interface {
+	Reencrypt(ctx context.Context, batchSize int) (int64, error)
+	Decrypt(ctx context.Context, batchSize int) (int64, error)
+}
+
+type piiRotationRepository struct {
+	db     *pgxpool.Pool
+	cipher *pii.Cipher
+}
+
+func NewPIIRotationRepository(db *pgxpool.Pool, cipher *pii.Cipher) PIIRotationRepository {
+	return &piiRotationRepository{db: db, cipher: cipher}
+}
+
+type storedRegistrationPII struct {
+	ID        int
+	FullName  string
+	Email     string
+	Phone     string
+	EmailBidx string
+	PhoneBidx string
+}
+
+// Reencrypt rewrites every registration that is still plaintext, sealed
+// under a key other than the active one, or missing its blind indexes.
+// Once it finishes, retired keys can be removed from PII_ENCRYPTION_KEYS.
+func (r *piiRotationRepository) Reencrypt(ctx context.Context, batchSize int) (int64, error) {
+	return r.rewrite(ctx, batchSize, func(row *storedRegistrationPII, reg *models.Registration) (*storedPII, error) {
+		if !r.cipher.NeedsRotation(row.FullName) &&
+			!r.cipher.NeedsRotation(row.Email) &&
+			!r.cipher.NeedsRotation(row.Phone) &&
+			row.EmailBidx == r.cipher.BlindIndex(pii.FieldEmail, reg.Email) &&
+			row.PhoneBidx == r.cipher.BlindIndex(pii.FieldPhone, reg.Phone) {
+			return nil, nil
+		}
+		return sealRegistration(r.cipher, reg)
+	})
+}
+
+// Decrypt writes every registration back as plaintext, for rolling back
+// the encryption migration.
+func (r *piiRotationRepository) Decrypt(ctx context.Context, batchSize int) (int64, error) {
+	return r.rewrite(ctx, batchSize, func(row *storedRegistrationPII, reg *models.Registration) (*storedPII, error) {
+		if !pii.IsEncrypted(row.FullName) && !pii.IsEncrypted(row.Email) && !pii.IsEncrypted(row.Phone) {
+			return nil, nil
+		}
+		return &storedPII{FullName: reg.FullName, Email: reg.Email, Phone: reg.Phone}, nil
+	})
+}
+
+// rewrite walks non-erased registrations in ID order, one locked batch per
+// transaction, and stores whatever transform returns for each row. A nil
+// result leaves the row untouched.
+func (r *piiRotationRepository) rewrite(ctx context.Context, batchSize int, transform rotationTransform) (int64, error) {
+	var updated int64
+	lastID := 0
+
+	for {
+		n, nextID, err := r.rewriteBatch(ctx, lastID, batchSize, transform)
+		updated += n
+		if err != nil || nextID == 0 {
+			return updated, err
+		}
+		lastID = nextID
+	}
+}
+
+type rotationTransform func(*storedRegistrationPII, *models.Registration) (*storedPII, error)
+
+// rewriteBatch handles the batch after lastID and returns the number of
+// rows updated and the last ID seen, or 0 when there are no rows left.
+func (r *piiRotationRepository) rewriteBatch(ctx context.Context, lastID, batchSize int, transform rotationTransform) (int64, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryBulk)
+	defer cancel()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, 0, databaseError("Failed to start transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+        SELECT id, full_name, email, phone, COALESCE(email_bidx, ''), COALESCE(phone_bidx, '')
+        FROM registrations
+        WHERE id > $1 AND erased_on IS NULL
+        ORDER BY id
+        LIMIT $2
+        FOR UPDATE
+    `, lastID, batchSize)
+	if err != nil {
+		return 0, 0, databaseError("Failed to fetch registrations", err)
+	}
+
+	batch, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (storedRegistrationPII, error) {
+		var stored storedRegistrationPII
+		err := row.Scan(&stored.ID, &stored.FullName, &stored.Email, &stored.Phone, &stored.EmailBidx, &stored.PhoneBidx)
+		return stored, err
+	})
+	if err != nil {
+		return 0, 0, databaseError("Failed to scan registration", err)
+	}
+	if len(batch) == 0 {
+		return 0, 0, nil
+	}
+
+	var updated int64
+	for i := range batch {
+		row := &batch[i]
+		reg := &models.Registration{ID: row.ID, FullName: row.FullName, Email: row.Email, Phone: row.Phone}
+		if err := openRegistration(r.cipher, reg); err != nil {
+			return 0, 0, utils.NewInternalServerError("ENCRYPTION_ERROR", "Failed to decrypt registration", err)
+		}
+
+		stored, err := transform(row, reg)
+		if err != nil {
+			return 0, 0, utils.NewInternalServerError("ENCRYPTION_ERROR", "Failed to encrypt registration", err)
+		}
+		if stored == nil {
+			continue
+		}
+
+		if _, err := tx.Exec(ctx, `
+            UPDATE registrations
+            SET full_name = $2, email = $3, phone = $4, email_bidx = $5, phone_bidx = $6
+            WHERE id = $1
+        `, row.ID, stored.FullName, stored.Email, stored.Phone, stored.EmailBidx, stored.PhoneBidx); err != nil {
+			return 0, 0, databaseError("Failed to update registration", err)
+		}
+		updated++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, databaseError("Failed to commit registrations", err)
+	}
+
+	return updated, batch[len(batch)-1].ID, nil
+}
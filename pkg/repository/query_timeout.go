@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// queryCanceled is the SQLSTATE Postgres reports when a statement is
+// cancelled, which pgx triggers when the query's context ends.
+const queryCanceled = "57014"
+
+type queryKind int
+
+const (
+	// queryRead covers single-row lookups on the request path.
+	queryRead queryKind = iota
+	// queryWrite covers single inserts and updates.
+	queryWrite
+	// queryReport covers full-table reads: exports, stats, audit and
+	// data-subject searches.
+	queryReport
+	// queryBulk covers imports, batches, purges and re-encryption batches.
+	queryBulk
+)
+
+var queryTimeouts = map[queryKind]time.Duration{
+	queryRead:   envDuration("QUERY_TIMEOUT_READ", 5*time.Second),
+	queryWrite:  envDuration("QUERY_TIMEOUT_WRITE", 10*time.Second),
+	queryReport: envDuration("QUERY_TIMEOUT_REPORT", 30*time.Second),
+	queryBulk:   envDuration("QUERY_TIMEOUT_BULK", 2*time.Minute),
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(config.GetEnv(key, fallback.String()))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// withQueryTimeout bounds ctx by the timeout configured for kind. The
+// caller's own deadline still applies when it is earlier.
+func withQueryTimeout(ctx context.Context, kind queryKind) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeouts[kind])
+}
+
+// databaseError wraps a failed database call. Timeouts, cancellations and
+// lost connections are reported separately so callers can tell a slow or
+// unreachable database from a broken query.
+func databaseError(message string, err error) *utils.AppError {
+	var pgErr *pgconn.PgError
+	var connectErr *pgconn.ConnectError
+	var netErr *net.OpError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return utils.NewClientClosedRequestError("REQUEST_CANCELED", "The request was cancelled", err)
+	case errors.Is(err, context.DeadlineExceeded), pgconn.Timeout(err),
+		errors.As(err, &pgErr) && pgErr.Code == queryCanceled:
+		return utils.NewGatewayTimeoutError("QUERY_TIMEOUT", "The database query timed out", err)
+	case errors.As(err, &connectErr), errors.As(err, &netErr):
+		return utils.NewServiceUnavailableError("DATABASE_UNAVAILABLE", "The database is unavailable", err)
+	default:
+		return utils.NewInternalServerError("DATABASE_ERROR", message, err)
+	}
+}
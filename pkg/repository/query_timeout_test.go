@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestDatabaseErrorMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		kind   error
+	}{
+		{"cancelled", fmt.Errorf("query: %w", context.Canceled), utils.StatusClientClosedRequest, "REQUEST_CANCELED", nil},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, "QUERY_TIMEOUT", utils.ErrUnavailable},
+		{"statement cancelled", &pgconn.PgError{Code: queryCanceled}, http.StatusGatewayTimeout, "QUERY_TIMEOUT", utils.ErrUnavailable},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, http.StatusServiceUnavailable, "DATABASE_UNAVAILABLE", utils.ErrUnavailable},
+		{"constraint", &pgconn.PgError{Code: "23503"}, http.StatusInternalServerError, "DATABASE_ERROR", nil},
+		{"other", errors.New("boom"), http.StatusInternalServerError, "DATABASE_ERROR", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := databaseError("Failed to fetch registration", tt.err)
+
+			if got.HTTPCode != tt.status || got.Code != tt.code {
+				t.Errorf("got %d %s, want %d %s", got.HTTPCode, got.Code, tt.status, tt.code)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("result does not wrap the original error")
+			}
+			if tt.kind != nil && !errors.Is(got, tt.kind) {
+				t.Errorf("result does not match %v", tt.kind)
+			}
+			if errors.Is(got, utils.ErrNotFound) {
+				t.Errorf("database failure matches ErrNotFound")
+			}
+		})
+	}
+}
+
+func TestDatabaseErrorKeepsTheCallerMessageForUnknownFailures(t *testing.T) {
+	got := databaseError("Failed to fetch registration", errors.New("boom"))
+	if got.Message != "Failed to fetch registration" {
+		t.Errorf("message = %q", got.Message)
+	}
+}
+
+func TestRegistrationWriteErrorMapsBlindIndexViolations(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"email", &pgconn.PgError{Code: uniqueViolation, ConstraintName: emailBidxUniqueIndex}, "DUPLICATE_EMAIL"},
+		{"phone", &pgconn.PgError{Code: uniqueViolation, ConstraintName: phoneBidxUniqueIndex}, "DUPLICATE_PHONE"},
+		{"other constraint", &pgconn.PgError{Code: uniqueViolation, ConstraintName: "registrations_pkey"}, "DATABASE_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := registrationWriteError("Failed to create registration", tt.err)
+
+			if got.Code != tt.code {
+				t.Errorf("code = %s, want %s", got.Code, tt.code)
+			}
+			if (tt.code != "DATABASE_ERROR") != errors.Is(got, utils.ErrConflict) {
+				t.Errorf("conflict kind mismatch for %s", tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const uniqueViolation = "23505"
+
+type ReferralRepository interface {
+	Create(ctx context.Context, code *models.ReferralCode) (*models.ReferralCode, error)
+	GetAll(ctx context.Context) ([]models.ReferralCode, error)
+	GetByCode(ctx context.Context, code string) (*models.ReferralCode, error)
+	GetByCodes(ctx context.Context, codes []string) ([]models.ReferralCode, error)
+	GetAttributionReport(ctx context.Context) (*models.AttributionReport, error)
+}
+
+type referralRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReferralRepository(db *pgxpool.Pool) ReferralRepository {
+	return &referralRepository{db: db}
+}
+
+func (r *referralRepository) Create(ctx context.Context, code *models.ReferralCode) (*models.ReferralCode, error) {
+	query := `
+        INSERT INTO referral_codes (code, owner, description, active)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_on
+    `
+
+	ctx, cancel := withQueryTimeout(ctx, queryWrite)
+	defer cancel()
+	err := r.db.QueryRow(ctx, query, code.Code, code.Owner, code.Description, code.Active).
+		Scan(&code.ID, &code.CreatedOn)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return nil, utils.NewDuplicateError("DUPLICATE_REFERRAL_CODE", "Referral code already exists", err)
+		}
+		return nil, databaseError("Failed to create referral code", err)
+	}
+
+	return code, nil
+}
+
+func (r *referralRepository) GetAll(ctx context.Context) ([]models.ReferralCode, error) {
+	query := `
+        SELECT id, code, owner, description, active, created_on
+        FROM referral_codes
+        ORDER BY created_on DESC
+    `
+
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, databaseError("Failed to fetch referral codes", err)
+	}
+	defer rows.Close()
+
+	var codes []models.ReferralCode
+	for rows.Next() {
+		var rc models.ReferralCode
+		if err := rows.Scan(&rc.ID, &rc.Code, &rc.Owner, &rc.Description, &rc.Active, &rc.CreatedOn); err != nil {
+			return nil, databaseError("Failed to scan referral code", err)
+		}
+		codes = append(codes, rc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, databaseError("Error iterating referral codes", err)
+	}
+
+	return codes, nil
+}
+
+func (r *referralRepository) GetByCode(ctx context.Context, code string) (*models.ReferralCode, error) {
+	query := `
+        SELECT id, code, owner, description, active, created_on
+        FROM referral_codes
+        WHERE code = $1
+    `
+
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
+	var rc models.ReferralCode
+	err := r.db.QueryRow(ctx, query, code).
+		Scan(&rc.ID, &rc.Code, &rc.Owner, &rc.Description, &rc.Active, &rc.CreatedOn)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.NewNotFoundError("REFERRAL_CODE_NOT_FOUND", "Referral code not found", err)
+		}
+		return nil, databaseError("Failed to fetch referral code", err)
+	}
+
+	return &rc, nil
+}
+
+// GetByCodes returns the referral codes among codes that exist, in no
+// particular order.
+func (r *referralRepository) GetByCodes(ctx context.Context, codes []string) ([]models.ReferralCode, error) {
+	query := `
+        SELECT id, code, owner, description, active, created_on
+        FROM referral_codes
+        WHERE code = ANY($1)
+    `
+
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
+	rows, err := r.db.Query(ctx, query, codes)
+	if err != nil {
+		return nil, databaseError("Failed to fetch referral codes", err)
+	}
+	found, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.ReferralCode, error) {
+		var rc models.ReferralCode
+		err := row.Scan(&rc.ID, &rc.Code, &rc.Owner, &rc.Description, &rc.Active, &rc.CreatedOn)
+		return rc, err
+	})
+	if err != nil {
+		return nil, databaseError("Failed to scan referral code", err)
+	}
+
+	return found, nil
+}
+
+func (r *referralRepository) GetAttributionReport(ctx context.Context) (*models.AttributionReport, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryReport)
+	defer cancel()
+	report := &models.AttributionReport{}
+
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM registrations`).Scan(&report.Total); err != nil {
+		return nil, databaseError("Failed to count registrations", err)
+	}
+
+	// Registrations made before UTM capture only have the free-form
+	// mkt_source, so it is used as the source when utm_source is empty.
+	queries := []struct {
+		target *[]models.FieldCount
+		query  string
+	}{
+		{&report.BySource, `
+            SELECT COALESCE(NULLIF(utm_source, ''), NULLIF(TRIM(mkt_source), ''), 'Unspecified') AS value, COUNT(*) AS count
+            FROM registrations
+            GROUP BY 1
+            ORDER BY count DESC, value
+        `},
+		{&report.ByMedium, `
+            SELECT COALESCE(NULLIF(utm_medium, ''), 'Unspecified') AS value, COUNT(*) AS count
+            FROM registrations
+            GROUP BY 1
+            ORDER BY count DESC, value
+        `},
+		{&report.ByCampaign, `
+            SELECT COALESCE(NULLIF(utm_campaign, ''), 'Unspecified') AS value, COUNT(*) AS count
+            FROM registrations
+            GROUP BY 1
+            ORDER BY count DESC, value
+        `},
+		{&report.ByReferralCode, `
+            SELECT rc.code AS value, COUNT(reg.id) AS count
+            FROM referral_codes rc
+            LEFT JOIN registrations reg ON reg.referral_code = rc.code
+            GROUP BY rc.code
+            ORDER BY count DESC, value
+        `},
+	}
+
+	for _, q := range queries {
+		rows, err := r.db.Query(ctx, q.query)
+		if err != nil {
+			return nil, databaseError("Failed to build attribution report", err)
+		}
+		counts, err := collectFieldCounts(rows)
+		if err != nil {
+			return nil, err
+		}
+		*q.target = counts
+	}
+
+	return report, nil
+}
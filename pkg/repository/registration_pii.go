@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
+)
+
+// storedPII is the at-rest form of a registration's personal fields.
+type storedPII struct {
+	FullName  string
+	Email     string
+	Phone     string
+	EmailBidx any
+	PhoneBidx any
+}
+
+func sealRegistration(cipher *pii.Cipher, reg *models.Registration) (*storedPII, error) {
+	fullName, err := cipher.Encrypt(pii.FieldFullName, reg.FullName)
+	if err != nil {
+		return nil, err
+	}
+	email, err := cipher.Encrypt(pii.FieldEmail, reg.Email)
+	if err != nil {
+		return nil, err
+	}
+	phone, err := cipher.Encrypt(pii.FieldPhone, reg.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storedPII{
+		FullName:  fullName,
+		Email:     email,
+		Phone:     phone,
+		EmailBidx: nullIfEmpty(cipher.BlindIndex(pii.FieldEmail, reg.Email)),
+		PhoneBidx: nullIfEmpty(cipher.BlindIndex(pii.FieldPhone, reg.Phone)),
+	}, nil
+}
+
+// openRegistration decrypts the personal fields of a scanned registration
+// in place. Rows written before encryption was enabled are plaintext and
+// pass through unchanged.
+func openRegistration(cipher *pii.Cipher, reg *models.Registration) error {
+	var err error
+	if reg.FullName, err = cipher.Decrypt(pii.FieldFullName, reg.FullName); err != nil {
+		return fmt.Errorf("registration %d: %w", reg.ID, err)
+	}
+	if reg.Email, err = cipher.Decrypt(pii.FieldEmail, reg.Email); err != nil {
+		return fmt.Errorf("registration %d: %w", reg.ID, err)
+	}
+	if reg.Phone, err = cipher.Decrypt(pii.FieldPhone, reg.Phone); err != nil {
+		return fmt.Errorf("registration %d: %w", reg.ID, err)
+	}
+	return nil
+}
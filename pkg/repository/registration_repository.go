@@ -3,158 +3,622 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
 	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// The partial unique indexes on the blind indexes of live registrations.
+const (
+	emailBidxUniqueIndex = "idx_registrations_email_bidx_unique"
+	phoneBidxUniqueIndex = "idx_registrations_phone_bidx_unique"
+)
+
 type RegistrationRepository interface {
-	Create(registration *models.Registration) (*models.Registration, error)
-	GetAll() ([]models.Registration, error)
-	GetByEmail(email string) (*models.Registration, error)
-	GetByPhone(phone string) (*models.Registration, error)
+	Create(ctx context.Context, registration *models.Registration) (*models.Registration, error)
+	GetAll(ctx context.Context) ([]models.Registration, error)
+	GetByEmail(ctx context.Context, email string) (*models.Registration, error)
+	GetByPhone(ctx context.Context, phone string) (*models.Registration, error)
+	FindRegistered(ctx context.Context, field string, values []string) (map[string]bool, error)
+	GetStats(ctx context.Context) (*models.RegistrationStats, error)
+	CreateBatch(ctx context.Context, group *models.RegistrationGroup, registrations []*models.Registration, primaryIndex int, partial bool) ([]error, error)
+	BulkCreate(ctx context.Context, registrations []*models.Registration) ([]int, error)
 }
 
 type registrationRepository struct {
-	db *pgxpool.Pool
+	db     *pgxpool.Pool
+	cipher *pii.Cipher
+}
+
+func NewRegistrationRepository(db *pgxpool.Pool, cipher *pii.Cipher) RegistrationRepository {
+	return &registrationRepository{db: db, cipher: cipher}
 }
 
-func NewRegistrationRepository(db *pgxpool.Pool) RegistrationRepository {
-	return &registrationRepository{db: db}
+const registrationColumns = `id, full_name, email, phone, org_name, designation, mkt_source, food_pref, t_shirt,
+        utm_source, utm_medium, utm_campaign, utm_term, utm_content, referrer, COALESCE(referral_code, ''), COALESCE(invite_code, ''), group_id, erased_on, created_on`
+
+// scanRegistration reads a row selected with registrationColumns and
+// decrypts its personal fields.
+func scanRegistration(cipher *pii.Cipher, row pgx.Row, reg *models.Registration) error {
+	err := row.Scan(
+		&reg.ID,
+		&reg.FullName,
+		&reg.Email,
+		&reg.Phone,
+		&reg.OrgName,
+		&reg.Designation,
+		&reg.MktSource,
+		&reg.FoodPref,
+		&reg.TShirt,
+		&reg.UTMSource,
+		&reg.UTMMedium,
+		&reg.UTMCampaign,
+		&reg.UTMTerm,
+		&reg.UTMContent,
+		&reg.Referrer,
+		&reg.ReferralCode,
+		&reg.InviteCode,
+		&reg.GroupID,
+		&reg.ErasedOn,
+		&reg.CreatedOn,
+	)
+	if err != nil {
+		return err
+	}
+	return openRegistration(cipher, reg)
 }
 
-func (r *registrationRepository) Create(registration *models.Registration) (*models.Registration, error) {
+func (r *registrationRepository) Create(ctx context.Context, registration *models.Registration) (*models.Registration, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryWrite)
+	defer cancel()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, databaseError("Failed to start transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertRegistration(ctx, tx, r.cipher, registration); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, databaseError("Failed to commit registration", err)
+	}
+
+	return registration, nil
+}
+
+// insertRegistration claims one use of the registration's invite code, if
+// any, and inserts the row. Claiming inside the same transaction keeps
+// concurrent registrations from overrunning a code's max_uses.
+func insertRegistration(ctx context.Context, tx pgx.Tx, cipher *pii.Cipher, registration *models.Registration) error {
+	stored, err := sealRegistration(cipher, registration)
+	if err != nil {
+		return utils.NewInternalServerError("ENCRYPTION_ERROR", "Failed to encrypt registration", err)
+	}
+
+	if registration.InviteCode != "" {
+		tag, err := tx.Exec(ctx, `
+            UPDATE invite_codes
+            SET used_count = used_count + 1
+            WHERE code = $1
+              AND active
+              AND (max_uses IS NULL OR used_count < max_uses)
+              AND (expires_at IS NULL OR expires_at > NOW())
+        `, registration.InviteCode)
+		if err != nil {
+			return databaseError("Failed to claim invite code", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return utils.NewBadRequestError("INVITE_CODE_UNAVAILABLE", "Invite code is no longer available", nil)
+		}
+	}
+
 	query := `
-        INSERT INTO registrations (full_name, email, phone, org_name, designation, mkt_source, food_pref, t_shirt)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        INSERT INTO registrations (full_name, email, phone, org_name, designation, mkt_source, food_pref, t_shirt,
+            utm_source, utm_medium, utm_campaign, utm_term, utm_content, referrer, referral_code, invite_code, group_id,
+            email_bidx, phone_bidx)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), NULLIF($16, ''), $17, $18, $19)
         RETURNING id, created_on
     `
 
-	ctx := context.Background()
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		ctx,
 		query,
-		registration.FullName,
-		registration.Email,
-		registration.Phone,
+		stored.FullName,
+		stored.Email,
+		stored.Phone,
 		registration.OrgName,
 		registration.Designation,
 		registration.MktSource,
 		registration.FoodPref,
 		registration.TShirt,
+		registration.UTMSource,
+		registration.UTMMedium,
+		registration.UTMCampaign,
+		registration.UTMTerm,
+		registration.UTMContent,
+		registration.Referrer,
+		registration.ReferralCode,
+		registration.InviteCode,
+		registration.GroupID,
+		stored.EmailBidx,
+		stored.PhoneBidx,
 	).Scan(&registration.ID, &registration.CreatedOn)
 
 	if err != nil {
-		return nil, utils.NewInternalServerError("DATABASE_ERROR", "Failed to create registration", err)
+		return registrationWriteError("Failed to create registration", err)
 	}
 
-	return registration, nil
+	return nil
 }
 
-func (r *registrationRepository) GetAll() ([]models.Registration, error) {
+// registrationWriteError reports a write that lost the race with a
+// concurrent registration of the same email or phone as the duplicate
+// the service would have reported, and any other failure as databaseError.
+func registrationWriteError(message string, err error) *utils.AppError {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		switch pgErr.ConstraintName {
+		case emailBidxUniqueIndex:
+			return utils.NewDuplicateError("DUPLICATE_EMAIL", "Email already registered", err)
+		case phoneBidxUniqueIndex:
+			return utils.NewDuplicateError("DUPLICATE_PHONE", "Phone number already registered", err)
+		}
+	}
+	return databaseError(message, err)
+}
+
+// CreateBatch inserts a group and its registrations in one transaction
+// and returns the failure for each registration (nil when it was
+// inserted). In atomic mode the first failure rolls everything back. In
+// partial mode each registration runs in its own savepoint so a failing
+// row does not abort the others.
+func (r *registrationRepository) CreateBatch(ctx context.Context, group *models.RegistrationGroup, registrations []*models.Registration, primaryIndex int, partial bool) ([]error, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryBulk)
+	defer cancel()
+	itemErrs := make([]error, len(registrations))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, databaseError("Failed to start transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+        INSERT INTO registration_groups (name)
+        VALUES ($1)
+        RETURNING id, created_on
+    `, group.Name).Scan(&group.ID, &group.CreatedOn)
+	if err != nil {
+		return nil, databaseError("Failed to create registration group", err)
+	}
+
+	created := 0
+	for i, registration := range registrations {
+		if registration == nil {
+			continue
+		}
+		registration.GroupID = &group.ID
+
+		if !partial {
+			if err := insertRegistration(ctx, tx, r.cipher, registration); err != nil {
+				itemErrs[i] = err
+				group.ID = 0
+				return itemErrs, nil
+			}
+			created++
+			continue
+		}
+
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, databaseError("Failed to create savepoint", err)
+		}
+		if err := insertRegistration(ctx, savepoint, r.cipher, registration); err != nil {
+			itemErrs[i] = err
+			registration.GroupID = nil
+			if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+				return nil, databaseError("Failed to roll back savepoint", rbErr)
+			}
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			return nil, databaseError("Failed to release savepoint", err)
+		}
+		created++
+	}
+
+	if created == 0 {
+		group.ID = 0
+		return itemErrs, nil
+	}
+
+	if primary := registrations[primaryIndex]; primary != nil && itemErrs[primaryIndex] == nil {
+		group.PrimaryRegistrationID = &primary.ID
+		if _, err := tx.Exec(ctx, `
+            UPDATE registration_groups SET primary_registration_id = $1 WHERE id = $2
+        `, primary.ID, group.ID); err != nil {
+			return nil, databaseError("Failed to set group primary contact", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, databaseError("Failed to commit registrations", err)
+	}
+
+	return itemErrs, nil
+}
+
+// BulkCreate loads registrations with COPY and returns their IDs. COPY
+// cannot run the per-row invite code claim or return the rows it wrote,
+// so the uses of each code are claimed and the IDs drawn from the
+// sequence up front in the same transaction.
+func (r *registrationRepository) BulkCreate(ctx context.Context, registrations []*models.Registration) ([]int, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryBulk)
+	defer cancel()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, databaseError("Failed to start transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	inviteUses := make(map[string]int)
+	for _, registration := range registrations {
+		if registration.InviteCode != "" {
+			inviteUses[registration.InviteCode]++
+		}
+	}
+	for code, uses := range inviteUses {
+		tag, err := tx.Exec(ctx, `
+            UPDATE invite_codes
+            SET used_count = used_count + $2
+            WHERE code = $1
+              AND active
+              AND (max_uses IS NULL OR used_count + $2 <= max_uses)
+              AND (expires_at IS NULL OR expires_at > NOW())
+        `, code, uses)
+		if err != nil {
+			return nil, databaseError("Failed to claim invite code", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, utils.NewBadRequestError("INVITE_CODE_UNAVAILABLE", fmt.Sprintf("Invite code %s cannot cover %d registrations", code, uses), nil)
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+        SELECT nextval(pg_get_serial_sequence('registrations', 'id'))::int
+        FROM generate_series(1, $1)
+    `, len(registrations))
+	if err != nil {
+		return nil, databaseError("Failed to allocate registration IDs", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, databaseError("Failed to allocate registration IDs", err)
+	}
+
+	columns := []string{
+		"id", "full_name", "email", "phone", "org_name", "designation", "mkt_source", "food_pref", "t_shirt",
+		"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content", "referrer", "referral_code", "invite_code",
+		"email_bidx", "phone_bidx",
+	}
+
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"registrations"},
+		columns,
+		pgx.CopyFromSlice(len(registrations), func(i int) ([]any, error) {
+			reg := registrations[i]
+			stored, err := sealRegistration(r.cipher, reg)
+			if err != nil {
+				return nil, err
+			}
+			return []any{
+				ids[i],
+				stored.FullName,
+				stored.Email,
+				stored.Phone,
+				reg.OrgName,
+				reg.Designation,
+				reg.MktSource,
+				reg.FoodPref,
+				reg.TShirt,
+				reg.UTMSource,
+				reg.UTMMedium,
+				reg.UTMCampaign,
+				reg.UTMTerm,
+				reg.UTMContent,
+				reg.Referrer,
+				nullIfEmpty(reg.ReferralCode),
+				nullIfEmpty(reg.InviteCode),
+				stored.EmailBidx,
+				stored.PhoneBidx,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return nil, registrationWriteError("Failed to import registrations", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, databaseError("Failed to commit import", err)
+	}
+
+	return ids, nil
+}
+
+func nullIfEmpty(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func (r *registrationRepository) GetAll(ctx context.Context) ([]models.Registration, error) {
 	query := `
-        SELECT id, full_name, email, phone, org_name, designation, mkt_source, food_pref, t_shirt, created_on
+        SELECT ` + registrationColumns + `
         FROM registrations
         ORDER BY created_on DESC
     `
 
-	ctx := context.Background()
+	ctx, cancel := withQueryTimeout(ctx, queryReport)
+	defer cancel()
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
-		return nil, utils.NewInternalServerError("DATABASE_ERROR", "Failed to fetch registrations", err)
+		return nil, databaseError("Failed to fetch registrations", err)
 	}
 	defer rows.Close()
 
 	var registrations []models.Registration
 	for rows.Next() {
 		var reg models.Registration
-		err := rows.Scan(
-			&reg.ID,
-			&reg.FullName,
-			&reg.Email,
-			&reg.Phone,
-			&reg.OrgName,
-			&reg.Designation,
-			&reg.MktSource,
-			&reg.FoodPref,
-			&reg.TShirt,
-			&reg.CreatedOn,
-		)
-		if err != nil {
-			return nil, utils.NewInternalServerError("DATABASE_ERROR", "Failed to scan registration", err)
+		if err := scanRegistration(r.cipher, rows, &reg); err != nil {
+			return nil, databaseError("Failed to scan registration", err)
 		}
 		registrations = append(registrations, reg)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, utils.NewInternalServerError("DATABASE_ERROR", "Error iterating registrations", err)
+		return nil, databaseError("Error iterating registrations", err)
 	}
 
 	return registrations, nil
 }
 
-func (r *registrationRepository) GetByEmail(email string) (*models.Registration, error) {
+func (r *registrationRepository) GetByEmail(ctx context.Context, email string) (*models.Registration, error) {
 	query := `
-        SELECT id, full_name, email, phone, org_name, designation, mkt_source, food_pref, t_shirt, created_on
+        SELECT ` + registrationColumns + `
         FROM registrations
-        WHERE email = $1
+        WHERE email_bidx = $1 OR (email_bidx IS NULL AND email = $2)
     `
 
-	ctx := context.Background()
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
 	var reg models.Registration
-	err := r.db.QueryRow(ctx, query, email).Scan(
-		&reg.ID,
-		&reg.FullName,
-		&reg.Email,
-		&reg.Phone,
-		&reg.OrgName,
-		&reg.Designation,
-		&reg.MktSource,
-		&reg.FoodPref,
-		&reg.TShirt,
-		&reg.CreatedOn,
-	)
+	err := scanRegistration(r.cipher, r.db.QueryRow(ctx, query, r.cipher.BlindIndex(pii.FieldEmail, email), email), &reg)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, utils.NewNotFoundError("REGISTRATION_NOT_FOUND", "Registration not found", err)
 		}
-		return nil, utils.NewInternalServerError("DATABASE_ERROR", "Failed to fetch registration", err)
+		return nil, databaseError("Failed to fetch registration", err)
 	}
 
 	return &reg, nil
 }
 
-func (r *registrationRepository) GetByPhone(phone string) (*models.Registration, error) { 
+func (r *registrationRepository) GetByPhone(ctx context.Context, phone string) (*models.Registration, error) {
 	query := `
-        SELECT id, full_name, email, phone, org_name, designation, mkt_source, food_pref, t_shirt, created_on
+        SELECT ` + registrationColumns + `
         FROM registrations
-        WHERE phone = $1
+        WHERE phone_bidx = $1 OR (phone_bidx IS NULL AND phone = $2)
     `
 
-	ctx := context.Background()
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
 	var reg models.Registration
-	err := r.db.QueryRow(ctx, query, phone).Scan(
-		&reg.ID,
-		&reg.FullName,
-		&reg.Email,
-		&reg.Phone,
-		&reg.OrgName,
-		&reg.Designation,
-		&reg.MktSource,
-		&reg.FoodPref,
-		&reg.TShirt,
-		&reg.CreatedOn,
-	)
+	err := scanRegistration(r.cipher, r.db.QueryRow(ctx, query, r.cipher.BlindIndex(pii.FieldPhone, phone), phone), &reg)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, utils.NewNotFoundError("REGISTRATION_NOT_FOUND", "Registration not found", err)
 		}
-		return nil, utils.NewInternalServerError("DATABASE_ERROR", "Failed to fetch registration", err)
+		return nil, databaseError("Failed to fetch registration", err)
 	}
 
 	return &reg, nil
 }
+
+// registeredLookups holds the columns FindRegistered matches for each
+// field.
+var registeredLookups = map[string]struct{ bidx, plain string }{
+	pii.FieldEmail: {"email_bidx", "email"},
+	pii.FieldPhone: {"phone_bidx", "phone"},
+}
+
+// FindRegistered reports which of values (emails or phones, per field)
+// already belong to a registration, in one query. The result is keyed by
+// pii.Normalize, and matches the way GetByEmail and GetByPhone do: on the
+// blind index, or on the plaintext column for rows not yet encrypted.
+func (r *registrationRepository) FindRegistered(ctx context.Context, field string, values []string) (map[string]bool, error) {
+	columns, ok := registeredLookups[field]
+	if !ok {
+		return nil, fmt.Errorf("no registration lookup for field %q", field)
+	}
+
+	byIndex := make(map[string]string, len(values))
+	indexes := make([]string, 0, len(values))
+	for _, value := range values {
+		if bidx := r.cipher.BlindIndex(field, value); bidx != "" {
+			byIndex[bidx] = pii.Normalize(field, value)
+			indexes = append(indexes, bidx)
+		}
+	}
+
+	query := `
+        SELECT ` + columns.bidx + `, ` + columns.plain + `
+        FROM registrations
+        WHERE ` + columns.bidx + ` = ANY($1) OR (` + columns.bidx + ` IS NULL AND ` + columns.plain + ` = ANY($2))
+    `
+
+	ctx, cancel := withQueryTimeout(ctx, queryRead)
+	defer cancel()
+	rows, err := r.db.Query(ctx, query, indexes, values)
+	if err != nil {
+		return nil, databaseError("Failed to fetch registrations", err)
+	}
+	defer rows.Close()
+
+	registered := make(map[string]bool)
+	for rows.Next() {
+		var bidx *string
+		var plain string
+		if err := rows.Scan(&bidx, &plain); err != nil {
+			return nil, databaseError("Failed to scan registration", err)
+		}
+		if bidx != nil {
+			registered[byIndex[*bidx]] = true
+		} else {
+			registered[pii.Normalize(field, plain)] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, databaseError("Error iterating registrations", err)
+	}
+
+	return registered, nil
+}
+
+var statsGroupColumns = map[string]bool{
+	"food_pref":  true,
+	"t_shirt":    true,
+	"mkt_source": true,
+	"org_name":   true,
+}
+
+var statsPeriodUnits = map[string]bool{
+	"day":  true,
+	"hour": true,
+}
+
+// GetStats runs every aggregate in one read-only repeatable-read
+// transaction, so the totals and breakdowns describe the same snapshot
+// even while registrations are being written.
+func (r *registrationRepository) GetStats(ctx context.Context) (*models.RegistrationStats, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryReport)
+	defer cancel()
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, databaseError("Failed to start transaction", err)
+	}
+	defer tx.Rollback(ctx)
+	stats := &models.RegistrationStats{}
+
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM registrations`).Scan(&stats.Total); err != nil {
+		return nil, databaseError("Failed to count registrations", err)
+	}
+
+	if stats.ByFoodPref, err = countByField(ctx, tx, "food_pref"); err != nil {
+		return nil, err
+	}
+	if stats.ByTShirt, err = countByField(ctx, tx, "t_shirt"); err != nil {
+		return nil, err
+	}
+	if stats.ByMktSource, err = countByField(ctx, tx, "mkt_source"); err != nil {
+		return nil, err
+	}
+	if stats.ByOrgName, err = countByField(ctx, tx, "org_name"); err != nil {
+		return nil, err
+	}
+	if stats.PerDay, err = countByPeriod(ctx, tx, "day"); err != nil {
+		return nil, err
+	}
+	if stats.PerHour, err = countByPeriod(ctx, tx, "hour"); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, databaseError("Failed to finish statistics", err)
+	}
+
+	return stats, nil
+}
+
+func countByField(ctx context.Context, q querier, column string) ([]models.FieldCount, error) {
+	if !statsGroupColumns[column] {
+		return nil, utils.NewInternalServerError("INVALID_STATS_FIELD", "Unsupported statistics field", fmt.Errorf("column %q", column))
+	}
+
+	query := fmt.Sprintf(`
+        SELECT COALESCE(NULLIF(TRIM(%[1]s), ''), 'Unspecified') AS value, COUNT(*) AS count
+        FROM registrations
+        GROUP BY 1
+        ORDER BY count DESC, value
+    `, column)
+
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, databaseError("Failed to aggregate registrations", err)
+	}
+
+	return collectFieldCounts(rows)
+}
+
+func collectFieldCounts(rows pgx.Rows) ([]models.FieldCount, error) {
+	defer rows.Close()
+
+	counts := []models.FieldCount{}
+	for rows.Next() {
+		var fc models.FieldCount
+		if err := rows.Scan(&fc.Value, &fc.Count); err != nil {
+			return nil, databaseError("Failed to scan aggregate", err)
+		}
+		counts = append(counts, fc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, databaseError("Error iterating aggregates", err)
+	}
+
+	return counts, nil
+}
+
+func countByPeriod(ctx context.Context, q querier, unit string) ([]models.PeriodCount, error) {
+	if !statsPeriodUnits[unit] {
+		return nil, utils.NewInternalServerError("INVALID_STATS_PERIOD", "Unsupported statistics period", fmt.Errorf("unit %q", unit))
+	}
+
+	query := `
+        SELECT date_trunc($1, created_on) AS period, COUNT(*) AS count
+        FROM registrations
+        GROUP BY 1
+        ORDER BY 1
+    `
+
+	rows, err := q.Query(ctx, query, unit)
+	if err != nil {
+		return nil, databaseError("Failed to aggregate registrations", err)
+	}
+	defer rows.Close()
+
+	counts := []models.PeriodCount{}
+	for rows.Next() {
+		var pc models.PeriodCount
+		if err := rows.Scan(&pc.Period, &pc.Count); err != nil {
+			return nil, databaseError("Failed to scan aggregate", err)
+		}
+		counts = append(counts, pc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, databaseError("Error iterating aggregates", err)
+	}
+
+	return counts, nil
+}
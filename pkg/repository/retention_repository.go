@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// retentionLockKey serialises purge runs across server instances.
+const retentionLockKey = 720391
+
+type RetentionRepository interface {
+	Preview(ctx context.Context, cutoff time.Time) (*models.RetentionPreview, error)
+	Purge(ctx context.Context, cutoff time.Time, action string) (*models.RetentionPurgeResult, error)
+}
+
+type retentionRepository struct {
+	db     *pgxpool.Pool
+	cipher *pii.Cipher
+}
+
+func NewRetentionRepository(db *pgxpool.Pool, cipher *pii.Cipher) RetentionRepository {
+	return &retentionRepository{db: db, cipher: cipher}
+}
+
+func (r *retentionRepository) Preview(ctx context.Context, cutoff time.Time) (*models.RetentionPreview, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryReport)
+	defer cancel()
+	preview := &models.RetentionPreview{}
+
+	rows, err := r.db.Query(ctx, `
+        SELECT id, created_on
+        FROM registrations
+        WHERE created_on < $1 AND erased_on IS NULL
+        ORDER BY created_on
+    `, cutoff)
+	if err != nil {
+		return nil, databaseError("Failed to preview retention purge", err)
+	}
+	defer rows.Close()
+
+	preview.RegistrationIDs = []int{}
+	for rows.Next() {
+		var id int
+		var createdOn time.Time
+		if err := rows.Scan(&id, &createdOn); err != nil {
+			return nil, databaseError("Failed to scan registration", err)
+		}
+		if preview.OldestCreatedOn == nil {
+			preview.OldestCreatedOn = &createdOn
+		}
+		preview.NewestCreatedOn = &createdOn
+		preview.RegistrationIDs = append(preview.RegistrationIDs, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, databaseError("Error iterating registrations", err)
+	}
+
+	return preview, nil
+}
+
+// Purge anonymizes or deletes every registration created before cutoff,
+// together with cached responses that hold their personal data and the
+// names of groups they lead or make up. When another instance holds the
+// purge lock it returns an empty result.
+func (r *retentionRepository) Purge(ctx context.Context, cutoff time.Time, action string) (*models.RetentionPurgeResult, error) {
+	ctx, cancel := withQueryTimeout(ctx, queryBulk)
+	defer cancel()
+	result := &models.RetentionPurgeResult{Action: action, Cutoff: cutoff, RegistrationIDs: []int{}}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, databaseError("Failed to start transaction", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, retentionLockKey).Scan(&locked); err != nil {
+		return nil, databaseError("Failed to acquire retention lock", err)
+	}
+	if !locked {
+		return result, nil
+	}
+
+	rows, err := tx.Query(ctx, `
+        SELECT id, email, phone
+        FROM registrations
+        WHERE created_on < $1 AND erased_on IS NULL
+        FOR UPDATE
+    `, cutoff)
+	if err != nil {
+		return nil, databaseError("Failed to select registrations for purge", err)
+	}
+
+	var ids []int
+	var indexes, terms []string
+	for rows.Next() {
+		var id int
+		var email, phone string
+		if err := rows.Scan(&id, &email, &phone); err != nil {
+			rows.Close()
+			return nil, databaseError("Failed to scan registration", err)
+		}
+		if email, err = r.cipher.Decrypt(pii.FieldEmail, email); err == nil {
+			phone, err = r.cipher.Decrypt(pii.FieldPhone, phone)
+		}
+		if err != nil {
+			rows.Close()
+			return nil, utils.NewInternalServerError("ENCRYPTION_ERROR", "Failed to decrypt registration", err)
+		}
+		ids = append(ids, id)
+		terms = append(terms, email, phone)
+		for _, index := range []string{r.cipher.BlindIndex(pii.FieldEmail, email), r.cipher.BlindIndex(pii.FieldPhone, phone)} {
+			if index != "" {
+				indexes = append(indexes, index)
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, databaseError("Error iterating registrations", err)
+	}
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	tag, err := tx.Exec(ctx, purgeCachedResponsesSQL, indexes, terms)
+	if err != nil {
+		return nil, databaseError("Failed to purge cached responses", err)
+	}
+	result.PurgedCachedResponses = tag.RowsAffected()
+
+	if _, err := tx.Exec(ctx, pseudonymizeGroupsSQL, ids); err != nil {
+		return nil, databaseError("Failed to purge registration groups", err)
+	}
+
+	query := pseudonymizeRegistrationsSQL
+	if action == models.RetentionActionDelete {
+		query = `DELETE FROM registrations WHERE id = ANY($1) RETURNING id`
+	}
+
+	rows, err = tx.Query(ctx, query, ids)
+	if err != nil {
+		return nil, databaseError("Failed to purge registrations", err)
+	}
+	result.RegistrationIDs, err = pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, databaseError("Failed to purge registrations", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, databaseError("Failed to commit purge", err)
+	}
+
+	return result, nil
+}
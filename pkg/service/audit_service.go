@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/tracing"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type AuditService interface {
+	Record(ctx context.Context, entry *models.AuditLog) error
+	ListAuditLogs(ctx context.Context, query *dto.AuditLogQuery) (*dto.AuditLogListResponse, error)
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) Record(ctx context.Context, entry *models.AuditLog) error {
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	_, err := s.repo.Create(ctx, entry)
+	return err
+}
+
+func (s *auditService) ListAuditLogs(ctx context.Context, query *dto.AuditLogQuery) (*dto.AuditLogListResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.ListAuditLogs")
+	defer span.End()
+
+	filter, validationErrors := query.ToFilter()
+	if len(validationErrors) > 0 {
+		return nil, &utils.AppError{
+			HTTPCode:         400,
+			Code:             "VALIDATION_ERROR",
+			Message:          "Invalid query parameters",
+			ValidationErrors: validationErrors,
+		}
+	}
+
+	entries, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AuditLogListResponse{
+		AuditLogs: make([]dto.AuditLogResponse, 0, len(entries)),
+		Total:     total,
+		Limit:     filter.Limit,
+		Offset:    filter.Offset,
+	}
+	for _, entry := range entries {
+		response.AuditLogs = append(response.AuditLogs, dto.AuditLogResponse{
+			ID:         entry.ID,
+			Actor:      entry.Actor,
+			Action:     entry.Action,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			RequestID:  entry.RequestID,
+			IPAddress:  entry.IPAddress,
+			Changes:    entry.Changes,
+			Metadata:   entry.Metadata,
+			CreatedOn:  entry.CreatedOn.Format(time.RFC3339),
+		})
+	}
+
+	return response, nil
+}
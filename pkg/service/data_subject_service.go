@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/tracing"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type DataSubjectService interface {
+	ExportData(ctx context.Context, req *dto.DataSubjectRequest) (*dto.DataSubjectExportResponse, error)
+	EraseData(ctx context.Context, req *dto.DataSubjectRequest) (*dto.DataSubjectErasureResponse, error)
+}
+
+type dataSubjectService struct {
+	repo repository.DataSubjectRepository
+}
+
+func NewDataSubjectService(repo repository.DataSubjectRepository) DataSubjectService {
+	return &dataSubjectService{repo: repo}
+}
+
+func (s *dataSubjectService) ExportData(ctx context.Context, req *dto.DataSubjectRequest) (*dto.DataSubjectExportResponse, error) {
+	ctx, span := tracing.Start(ctx, "DataSubjectService.ExportData")
+	defer span.End()
+
+	if validationErrors := req.Validate(); len(validationErrors) > 0 {
+		return nil, &utils.AppError{
+			HTTPCode:         400,
+			Code:             "VALIDATION_ERROR",
+			Message:          "Invalid input data",
+			ValidationErrors: validationErrors,
+		}
+	}
+
+	record, err := s.repo.Find(ctx, req.Email, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.DataSubjectExportResponse{
+		Registrations:   make([]dto.RegistrationResponse, 0, len(record.Registrations)),
+		Groups:          make([]dto.RegistrationGroupResponse, 0, len(record.Groups)),
+		AuditLogs:       make([]dto.AuditLogResponse, 0, len(record.AuditLogs)),
+		CachedResponses: make([]dto.CachedResponseSummary, 0, len(record.CachedResponses)),
+		GeneratedOn:     time.Now().UTC().Format(time.RFC3339),
+	}
+
+	for i := range record.Registrations {
+		response.Registrations = append(response.Registrations, *toRegistrationResponse(&record.Registrations[i]))
+	}
+	for _, group := range record.Groups {
+		response.Groups = append(response.Groups, dto.RegistrationGroupResponse{
+			ID:                    group.ID,
+			Name:                  group.Name,
+			PrimaryRegistrationID: group.PrimaryRegistrationID,
+			CreatedOn:             group.CreatedOn.Format(time.RFC3339),
+		})
+	}
+	for _, entry := range record.AuditLogs {
+		response.AuditLogs = append(response.AuditLogs, dto.AuditLogResponse{
+			ID:         entry.ID,
+			Actor:      entry.Actor,
+			Action:     entry.Action,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			RequestID:  entry.RequestID,
+			IPAddress:  entry.IPAddress,
+			Changes:    entry.Changes,
+			Metadata:   entry.Metadata,
+			CreatedOn:  entry.CreatedOn.Format(time.RFC3339),
+		})
+	}
+	for _, cached := range record.CachedResponses {
+		response.CachedResponses = append(response.CachedResponses, dto.CachedResponseSummary{
+			IdempotencyKey: cached.Key,
+			Method:         cached.Method,
+			Path:           cached.Path,
+			CreatedOn:      cached.CreatedOn.Format(time.RFC3339),
+			ExpiresAt:      cached.ExpiresAt.Format(time.RFC3339),
+		})
+	}
+
+	return response, nil
+}
+
+func (s *dataSubjectService) EraseData(ctx context.Context, req *dto.DataSubjectRequest) (*dto.DataSubjectErasureResponse, error) {
+	ctx, span := tracing.Start(ctx, "DataSubjectService.EraseData")
+	defer span.End()
+
+	if validationErrors := req.Validate(); len(validationErrors) > 0 {
+		return nil, &utils.AppError{
+			HTTPCode:         400,
+			Code:             "VALIDATION_ERROR",
+			Message:          "Invalid input data",
+			ValidationErrors: validationErrors,
+		}
+	}
+
+	result, err := s.repo.Erase(ctx, req.Email, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.RegistrationIDs) == 0 {
+		return nil, utils.NewNotFoundError("DATA_SUBJECT_NOT_FOUND", "No personal data found for this email or phone", nil)
+	}
+
+	return &dto.DataSubjectErasureResponse{
+		ErasedRegistrationIDs: result.RegistrationIDs,
+		PurgedCachedResponses: result.PurgedCachedResponses,
+		ErasedOn:              result.ErasedOn.Format(time.RFC3339),
+	}, nil
+}
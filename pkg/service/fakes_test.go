@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+// fakeRegistrationRepo keeps registrations in memory. insertErrs fails the
+// insert of any registration with that email, the way a constraint or a
+// lost invite-code claim would in the database.
+type fakeRegistrationRepo struct {
+	registrations []*models.Registration
+	insertErrs    map[string]error
+	batchCalls    int
+	bulkCalls     int
+	lookupCalls   int
+	nextID        int
+}
+
+func (r *fakeRegistrationRepo) insert(registration *models.Registration) error {
+	if err := r.insertErrs[registration.Email]; err != nil {
+		return err
+	}
+	r.nextID++
+	registration.ID = r.nextID
+	registration.CreatedOn = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.registrations = append(r.registrations, registration)
+	return nil
+}
+
+func (r *fakeRegistrationRepo) Create(ctx context.Context, registration *models.Registration) (*models.Registration, error) {
+	if err := r.insert(registration); err != nil {
+		return nil, err
+	}
+	return registration, nil
+}
+
+func (r *fakeRegistrationRepo) GetAll(ctx context.Context) ([]models.Registration, error) {
+	all := make([]models.Registration, 0, len(r.registrations))
+	for _, registration := range r.registrations {
+		all = append(all, *registration)
+	}
+	return all, nil
+}
+
+func (r *fakeRegistrationRepo) find(field, value string) *models.Registration {
+	value = pii.Normalize(field, value)
+	for _, registration := range r.registrations {
+		stored := registration.Phone
+		if field == pii.FieldEmail {
+			stored = registration.Email
+		}
+		if pii.Normalize(field, stored) == value {
+			return registration
+		}
+	}
+	return nil
+}
+
+func (r *fakeRegistrationRepo) GetByEmail(ctx context.Context, email string) (*models.Registration, error) {
+	r.lookupCalls++
+	if registration := r.find(pii.FieldEmail, email); registration != nil {
+		return registration, nil
+	}
+	return nil, utils.NewNotFoundError("REGISTRATION_NOT_FOUND", "Registration not found", nil)
+}
+
+func (r *fakeRegistrationRepo) GetByPhone(ctx context.Context, phone string) (*models.Registration, error) {
+	r.lookupCalls++
+	if registration := r.find(pii.FieldPhone, phone); registration != nil {
+		return registration, nil
+	}
+	return nil, utils.NewNotFoundError("REGISTRATION_NOT_FOUND", "Registration not found", nil)
+}
+
+func (r *fakeRegistrationRepo) FindRegistered(ctx context.Context, field string, values []string) (map[string]bool, error) {
+	r.lookupCalls++
+	registered := make(map[string]bool)
+	for _, value := range values {
+		if r.find(field, value) != nil {
+			registered[pii.Normalize(field, value)] = true
+		}
+	}
+	return registered, nil
+}
+
+func (r *fakeRegistrationRepo) GetStats(ctx context.Context) (*models.RegistrationStats, error) {
+	return &models.RegistrationStats{Total: len(r.registrations)}, nil
+}
+
+// CreateBatch mirrors the repository: atomic batches stop and keep nothing
+// at the first failed insert, partial batches skip only the failed rows.
+func (r *fakeRegistrationRepo) CreateBatch(ctx context.Context, group *models.RegistrationGroup, registrations []*models.Registration, primaryIndex int, partial bool) ([]error, error) {
+	r.batchCalls++
+	itemErrs := make([]error, len(registrations))
+	kept := len(r.registrations)
+	created := 0
+
+	group.ID = 1
+	for i, registration := range registrations {
+		if registration == nil {
+			continue
+		}
+		if err := r.insert(registration); err != nil {
+			itemErrs[i] = err
+			if !partial {
+				r.registrations = r.registrations[:kept]
+				group.ID = 0
+				return itemErrs, nil
+			}
+			continue
+		}
+		registration.GroupID = &group.ID
+		created++
+	}
+
+	if created == 0 {
+		group.ID = 0
+		return itemErrs, nil
+	}
+	if primary := registrations[primaryIndex]; primary != nil && itemErrs[primaryIndex] == nil {
+		group.PrimaryRegistrationID = &primary.ID
+	}
+	return itemErrs, nil
+}
+
+func (r *fakeRegistrationRepo) BulkCreate(ctx context.Context, registrations []*models.Registration) ([]int, error) {
+	r.bulkCalls++
+	ids := make([]int, 0, len(registrations))
+	for _, registration := range registrations {
+		if err := r.insert(registration); err != nil {
+			return nil, err
+		}
+		ids = append(ids, registration.ID)
+	}
+	return ids, nil
+}
+
+type fakeReferralRepo struct {
+	codes       map[string]*models.ReferralCode
+	lookupCalls int
+}
+
+func (r *fakeReferralRepo) Create(ctx context.Context, code *models.ReferralCode) (*models.ReferralCode, error) {
+	r.codes[code.Code] = code
+	return code, nil
+}
+
+func (r *fakeReferralRepo) GetAll(ctx context.Context) ([]models.ReferralCode, error) {
+	var all []models.ReferralCode
+	for _, code := range r.codes {
+		all = append(all, *code)
+	}
+	return all, nil
+}
+
+func (r *fakeReferralRepo) GetByCode(ctx context.Context, code string) (*models.ReferralCode, error) {
+	r.lookupCalls++
+	if found, ok := r.codes[code]; ok {
+		return found, nil
+	}
+	return nil, utils.NewNotFoundError("REFERRAL_CODE_NOT_FOUND", "Referral code not found", nil)
+}
+
+func (r *fakeReferralRepo) GetByCodes(ctx context.Context, codes []string) ([]models.ReferralCode, error) {
+	r.lookupCalls++
+	var found []models.ReferralCode
+	for _, code := range codes {
+		if rc, ok := r.codes[code]; ok {
+			found = append(found, *rc)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeReferralRepo) GetAttributionReport(ctx context.Context) (*models.AttributionReport, error) {
+	return &models.AttributionReport{}, nil
+}
+
+type fakeInviteCodeRepo struct {
+	codes       map[string]*models.InviteCode
+	lookupCalls int
+}
+
+func (r *fakeInviteCodeRepo) Create(ctx context.Context, code *models.InviteCode) (*models.InviteCode, error) {
+	r.codes[code.Code] = code
+	return code, nil
+}
+
+func (r *fakeInviteCodeRepo) GetAll(ctx context.Context) ([]models.InviteCode, error) {
+	var all []models.InviteCode
+	for _, code := range r.codes {
+		all = append(all, *code)
+	}
+	return all, nil
+}
+
+func (r *fakeInviteCodeRepo) GetByCode(ctx context.Context, code string) (*models.InviteCode, error) {
+	r.lookupCalls++
+	if found, ok := r.codes[code]; ok {
+		return found, nil
+	}
+	return nil, utils.NewNotFoundError("INVITE_CODE_NOT_FOUND", "Invite code not found", nil)
+}
+
+func (r *fakeInviteCodeRepo) GetByCodes(ctx context.Context, codes []string) ([]models.InviteCode, error) {
+	r.lookupCalls++
+	var found []models.InviteCode
+	for _, code := range codes {
+		if ic, ok := r.codes[code]; ok {
+			found = append(found, *ic)
+		}
+	}
+	return found, nil
+}
+
+type fakeRepos struct {
+	registrations *fakeRegistrationRepo
+	referrals     *fakeReferralRepo
+	inviteCodes   *fakeInviteCodeRepo
+}
+
+func newTestRegistrationService() (*registrationService, *fakeRepos) {
+	repos := &fakeRepos{
+		registrations: &fakeRegistrationRepo{insertErrs: map[string]error{}},
+		referrals:     &fakeReferralRepo{codes: map[string]*models.ReferralCode{}},
+		inviteCodes:   &fakeInviteCodeRepo{codes: map[string]*models.InviteCode{}},
+	}
+	svc := NewRegistrationService(repos.registrations, repos.referrals, repos.inviteCodes).(*registrationService)
+	return svc, repos
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/rs/zerolog"
+)
+
+type HealthService interface {
+	Liveness() *dto.HealthResponse
+	Readiness(ctx context.Context) (*dto.HealthResponse, bool)
+	SetDraining()
+	Draining() bool
+}
+
+type healthService struct {
+	repo         repository.HealthRepository
+	checkTimeout time.Duration
+	draining     atomic.Bool
+}
+
+func NewHealthService(repo repository.HealthRepository) HealthService {
+	timeout, err := time.ParseDuration(config.GetEnv("READINESS_CHECK_TIMEOUT", "2s"))
+	if err != nil || timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &healthService{repo: repo, checkTimeout: timeout}
+}
+
+// Liveness only reports that the process is serving requests. It does not
+// touch dependencies, so a database outage never gets the process
+// restarted.
+func (s *healthService) Liveness() *dto.HealthResponse {
+	return &dto.HealthResponse{Status: "alive"}
+}
+
+// Readiness runs every dependency check and reports whether this instance
+// should receive traffic.
+func (s *healthService) Readiness(ctx context.Context) (*dto.HealthResponse, bool) {
+	ctx, cancel := context.WithTimeout(ctx, s.checkTimeout)
+	defer cancel()
+
+	checks := map[string]dto.HealthCheckResult{
+		"shutdown": runCheck(func() (string, error) {
+			if s.draining.Load() {
+				return "", fmt.Errorf("server is draining")
+			}
+			return "", nil
+		}),
+		"database": runCheck(func() (string, error) {
+			// Probes are unauthenticated, so connection errors (which name
+			// hosts) are logged rather than returned.
+			if err := s.repo.Ping(ctx); err != nil {
+				zerolog.Ctx(ctx).Warn().Err(err).Msg("Readiness check: database ping failed")
+				return "", fmt.Errorf("database unreachable")
+			}
+			return "", nil
+		}),
+		"migrations": runCheck(func() (string, error) {
+			version, dirty, err := s.repo.SchemaVersion(ctx)
+			if err != nil {
+				zerolog.Ctx(ctx).Warn().Err(err).Msg("Readiness check: schema version query failed")
+				return "", fmt.Errorf("schema version unavailable")
+			}
+			detail := fmt.Sprintf("database at version %d, binary expects %d", version, repository.SchemaVersion)
+			switch {
+			case dirty:
+				return "", fmt.Errorf("migration %d is dirty", version)
+			case version < repository.SchemaVersion:
+				return "", fmt.Errorf("%s", detail)
+			}
+			return detail, nil
+		}),
+	}
+
+	ready := true
+	for _, check := range checks {
+		if check.Status != dto.HealthStatusPass {
+			ready = false
+		}
+	}
+
+	status := "ready"
+	if !ready {
+		status = "not_ready"
+	}
+	return &dto.HealthResponse{Status: status, Checks: checks}, ready
+}
+
+func (s *healthService) SetDraining() {
+	s.draining.Store(true)
+}
+
+func (s *healthService) Draining() bool {
+	return s.draining.Load()
+}
+
+func runCheck(check func() (string, error)) dto.HealthCheckResult {
+	start := time.Now()
+	detail, err := check()
+	result := dto.HealthCheckResult{
+		Status:     dto.HealthStatusPass,
+		DurationMs: time.Since(start).Milliseconds(),
+		Detail:     detail,
+	}
+	if err != nil {
+		result.Status = dto.HealthStatusFail
+		result.Detail = err.Error()
+	}
+	return result
+}
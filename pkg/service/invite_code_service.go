@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/tracing"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type InviteCodeService interface {
+	CreateInviteCode(ctx context.Context, req *dto.CreateInviteCodeRequest) (*dto.InviteCodeResponse, error)
+	ListInviteCodes(ctx context.Context) (*dto.InviteCodeListResponse, error)
+}
+
+type inviteCodeService struct {
+	repo repository.InviteCodeRepository
+}
+
+func NewInviteCodeService(repo repository.InviteCodeRepository) InviteCodeService {
+	return &inviteCodeService{repo: repo}
+}
+
+func (s *inviteCodeService) CreateInviteCode(ctx context.Context, req *dto.CreateInviteCodeRequest) (*dto.InviteCodeResponse, error) {
+	ctx, span := tracing.Start(ctx, "InviteCodeService.CreateInviteCode")
+	defer span.End()
+
+	if validationErrors := req.Validate(); len(validationErrors) > 0 {
+		return nil, &utils.AppError{
+			HTTPCode:         400,
+			Code:             "VALIDATION_ERROR",
+			Message:          "Invalid input data",
+			ValidationErrors: validationErrors,
+		}
+	}
+
+	code := &models.InviteCode{
+		Code:    dto.NormalizeCode(req.Code),
+		Label:   strings.TrimSpace(req.Label),
+		MaxUses: req.MaxUses,
+		Active:  true,
+	}
+	if req.ExpiresAt != "" {
+		expiresAt, _ := time.Parse(time.RFC3339, req.ExpiresAt)
+		code.ExpiresAt = &expiresAt
+	}
+
+	created, err := s.repo.Create(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return toInviteCodeResponse(created), nil
+}
+
+func (s *inviteCodeService) ListInviteCodes(ctx context.Context) (*dto.InviteCodeListResponse, error) {
+	ctx, span := tracing.Start(ctx, "InviteCodeService.ListInviteCodes")
+	defer span.End()
+
+	codes, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.InviteCodeListResponse{
+		InviteCodes: make([]dto.InviteCodeResponse, 0, len(codes)),
+		Total:       len(codes),
+	}
+	for i := range codes {
+		response.InviteCodes = append(response.InviteCodes, *toInviteCodeResponse(&codes[i]))
+	}
+
+	return response, nil
+}
+
+// checkInviteCode reports why a code cannot be used. The final claim is
+// made atomically in the repository; this only produces a precise error
+// for the common cases.
+func checkInviteCode(ctx context.Context, repo repository.InviteCodeRepository, code string) error {
+	inviteCode, err := repo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return inviteCodeError(nil)
+		}
+		return err
+	}
+	return inviteCodeError(inviteCode)
+}
+
+// inviteCodeError reports why an invite code that has already been looked
+// up cannot be used; nil means the code does not exist.
+func inviteCodeError(inviteCode *models.InviteCode) error {
+	if inviteCode == nil || !inviteCode.Active {
+		return utils.NewBadRequestError("INVALID_INVITE_CODE", "Invite code is invalid", nil)
+	}
+	if inviteCode.IsExpired(time.Now()) {
+		return utils.NewBadRequestError("INVITE_CODE_EXPIRED", "Invite code has expired", nil)
+	}
+	if inviteCode.IsExhausted() {
+		return utils.NewBadRequestError("INVITE_CODE_EXHAUSTED", "Invite code has reached its usage limit", nil)
+	}
+
+	return nil
+}
+
+func toInviteCodeResponse(code *models.InviteCode) *dto.InviteCodeResponse {
+	response := &dto.InviteCodeResponse{
+		ID:        code.ID,
+		Code:      code.Code,
+		Label:     code.Label,
+		MaxUses:   code.MaxUses,
+		UsedCount: code.UsedCount,
+		Active:    code.Active,
+		CreatedOn: code.CreatedOn.Format(time.RFC3339),
+	}
+
+	if code.MaxUses != nil {
+		remaining := max(*code.MaxUses-code.UsedCount, 0)
+		response.Remaining = &remaining
+	}
+
+	if code.ExpiresAt != nil {
+		expiresAt := code.ExpiresAt.Format(time.RFC3339)
+		response.ExpiresAt = &expiresAt
+	}
+
+	return response
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/tracing"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type ReferralService interface {
+	CreateReferralCode(ctx context.Context, req *dto.CreateReferralCodeRequest) (*dto.ReferralCodeResponse, error)
+	ListReferralCodes(ctx context.Context) (*dto.ReferralCodeListResponse, error)
+	GetAttributionReport(ctx context.Context) (*dto.AttributionReportResponse, error)
+}
+
+type referralService struct {
+	repo repository.ReferralRepository
+}
+
+func NewReferralService(repo repository.ReferralRepository) ReferralService {
+	return &referralService{repo: repo}
+}
+
+func (s *referralService) CreateReferralCode(ctx context.Context, req *dto.CreateReferralCodeRequest) (*dto.ReferralCodeResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReferralService.CreateReferralCode")
+	defer span.End()
+
+	if validationErrors := req.Validate(); len(validationErrors) > 0 {
+		return nil, &utils.AppError{
+			HTTPCode:         400,
+			Code:             "VALIDATION_ERROR",
+			Message:          "Invalid input data",
+			ValidationErrors: validationErrors,
+		}
+	}
+
+	code, err := s.repo.Create(ctx, &models.ReferralCode{
+		Code:        dto.NormalizeCode(req.Code),
+		Owner:       strings.TrimSpace(req.Owner),
+		Description: strings.TrimSpace(req.Description),
+		Active:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toReferralCodeResponse(code), nil
+}
+
+func (s *referralService) ListReferralCodes(ctx context.Context) (*dto.ReferralCodeListResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReferralService.ListReferralCodes")
+	defer span.End()
+
+	codes, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ReferralCodeListResponse{
+		ReferralCodes: make([]dto.ReferralCodeResponse, 0, len(codes)),
+		Total:         len(codes),
+	}
+	for i := range codes {
+		response.ReferralCodes = append(response.ReferralCodes, *toReferralCodeResponse(&codes[i]))
+	}
+
+	return response, nil
+}
+
+func (s *referralService) GetAttributionReport(ctx context.Context) (*dto.AttributionReportResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReferralService.GetAttributionReport")
+	defer span.End()
+
+	report, err := s.repo.GetAttributionReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AttributionReportResponse{
+		Total:          report.Total,
+		BySource:       toCountEntries(report.BySource),
+		ByMedium:       toCountEntries(report.ByMedium),
+		ByCampaign:     toCountEntries(report.ByCampaign),
+		ByReferralCode: toCountEntries(report.ByReferralCode),
+	}, nil
+}
+
+func toReferralCodeResponse(code *models.ReferralCode) *dto.ReferralCodeResponse {
+	return &dto.ReferralCodeResponse{
+		ID:          code.ID,
+		Code:        code.Code,
+		Owner:       code.Owner,
+		Description: code.Description,
+		Active:      code.Active,
+		CreatedOn:   code.CreatedOn.Format(time.RFC3339),
+	}
+}
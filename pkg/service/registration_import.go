@@ -0,0 +1,473 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/metrics"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/tracing"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatXLSX = "xlsx"
+)
+
+type ImportOptions struct {
+	Format      string
+	DryRun      bool
+	SkipInvalid bool
+	Mapping     map[string]string
+}
+
+// importHeaderAliases maps normalised column headers to registration
+// fields. It covers the headers written by GenerateCSV as well as the
+// JSON field names, so an exported CSV can be re-imported unchanged.
+var importHeaderAliases = map[string]string{
+	"fullname":        "full_name",
+	"name":            "full_name",
+	"email":           "email",
+	"phone":           "phone",
+	"organization":    "org_name",
+	"organisation":    "org_name",
+	"orgname":         "org_name",
+	"designation":     "designation",
+	"marketingsource": "mkt_source",
+	"mktsource":       "mkt_source",
+	"foodpreference":  "food_pref",
+	"foodpref":        "food_pref",
+	"tshirtsize":      "t_shirt",
+	"tshirt":          "t_shirt",
+	"utmsource":       "utm_source",
+	"utmmedium":       "utm_medium",
+	"utmcampaign":     "utm_campaign",
+	"utmterm":         "utm_term",
+	"utmcontent":      "utm_content",
+	"referrer":        "referrer",
+	"referralcode":    "referral_code",
+	"invitecode":      "invite_code",
+}
+
+var importRequiredFields = []string{"full_name", "email", "phone", "food_pref", "t_shirt"}
+
+func (s *registrationService) ImportRegistrations(ctx context.Context, file io.Reader, opts ImportOptions) (*dto.ImportReportResponse, error) {
+	ctx, span := tracing.Start(ctx, "RegistrationService.ImportRegistrations")
+	defer span.End()
+
+	rows, err := readImportRows(file, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, utils.NewBadRequestError("IMPORT_EMPTY_FILE", "The uploaded file has no header row", nil)
+	}
+
+	columns, mapping, err := mapImportColumns(rows[0], opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.ImportReportResponse{
+		Format:          opts.Format,
+		DryRun:          opts.DryRun,
+		ColumnMapping:   mapping,
+		RowErrors:       []dto.ImportRowError{},
+		RegistrationIDs: []int{},
+	}
+
+	// Rows are checked in two passes: each row on its own first, then
+	// against the database, with every email, phone and code the file
+	// uses resolved in one query per kind.
+	var candidates []importCandidate
+	for i, row := range rows[1:] {
+		rowNumber := i + 2
+		if err := ctx.Err(); err != nil {
+			return nil, utils.NewClientClosedRequestError("REQUEST_CANCELED", "The request was cancelled", err)
+		}
+		if isBlankRow(row) {
+			continue
+		}
+		report.TotalRows++
+
+		req := buildImportRequest(row, columns)
+		if err := validateImportRow(req); err != nil {
+			report.RowErrors = append(report.RowErrors, importRowError(rowNumber, err))
+			continue
+		}
+		candidates = append(candidates, importCandidate{row: rowNumber, req: req})
+	}
+
+	lookups, err := s.resolveImportLookups(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var registrations []*models.Registration
+	seenEmails := make(map[string]int)
+	seenPhones := make(map[string]int)
+
+	for _, candidate := range candidates {
+		registration, err := lookups.prepare(candidate, seenEmails, seenPhones)
+		if err != nil {
+			report.RowErrors = append(report.RowErrors, importRowError(candidate.row, err))
+			continue
+		}
+
+		seenEmails[pii.Normalize(pii.FieldEmail, candidate.req.Email)] = candidate.row
+		seenPhones[pii.Normalize(pii.FieldPhone, candidate.req.Phone)] = candidate.row
+		registrations = append(registrations, registration)
+	}
+	sort.Slice(report.RowErrors, func(i, j int) bool { return report.RowErrors[i].Row < report.RowErrors[j].Row })
+
+	report.ValidRows = len(registrations)
+	report.InvalidRows = len(report.RowErrors)
+
+	if opts.DryRun || len(registrations) == 0 {
+		return report, nil
+	}
+
+	if report.InvalidRows > 0 && !opts.SkipInvalid {
+		return nil, &utils.AppError{
+			HTTPCode:         400,
+			Code:             "IMPORT_VALIDATION_ERROR",
+			Message:          fmt.Sprintf("%d rows are invalid; fix them or retry with skip_invalid=true", report.InvalidRows),
+			ValidationErrors: flattenImportErrors(report.RowErrors),
+		}
+	}
+
+	ids, err := s.repo.BulkCreate(ctx, registrations)
+	if err != nil {
+		return nil, err
+	}
+	report.Imported = len(ids)
+	report.RegistrationIDs = ids
+	metrics.RegistrationsCreated.WithLabelValues(metrics.SourceImport).Add(float64(len(ids)))
+
+	return report, nil
+}
+
+type importCandidate struct {
+	row int
+	req *dto.CreateRegistrationRequest
+}
+
+// importLookups holds what the database already knows about the emails,
+// phones and codes used in an import. Emails and phones are keyed by
+// pii.Normalize; codes by dto.NormalizeCode. inviteCodeUses counts the
+// uses of each invite code taken by rows accepted so far, so rows past a
+// code's remaining uses are rejected here rather than failing the whole
+// import when BulkCreate claims them.
+type importLookups struct {
+	inviteOnly       bool
+	registeredEmails map[string]bool
+	registeredPhones map[string]bool
+	inviteCodes      map[string]*models.InviteCode
+	inviteCodeUses   map[string]int
+	referralCodes    map[string]*models.ReferralCode
+}
+
+// validateImportRow applies the checks that need nothing but the row
+// itself, and normalises its codes.
+func validateImportRow(req *dto.CreateRegistrationRequest) error {
+	validationErrors := dto.ValidateStruct(req)
+	if len(validationErrors) == 0 {
+		validationErrors = req.Validate()
+	}
+	if len(validationErrors) > 0 {
+		return &utils.AppError{
+			HTTPCode:         400,
+			Code:             "VALIDATION_ERROR",
+			Message:          "Invalid input data",
+			ValidationErrors: validationErrors,
+		}
+	}
+
+	req.InviteCode = dto.NormalizeCode(req.InviteCode)
+	req.ReferralCode = dto.NormalizeCode(req.ReferralCode)
+	return nil
+}
+
+func (s *registrationService) resolveImportLookups(ctx context.Context, candidates []importCandidate) (*importLookups, error) {
+	lookups := &importLookups{
+		inviteOnly:     s.inviteOnly,
+		inviteCodes:    make(map[string]*models.InviteCode),
+		inviteCodeUses: make(map[string]int),
+		referralCodes:  make(map[string]*models.ReferralCode),
+	}
+	if len(candidates) == 0 {
+		return lookups, nil
+	}
+
+	emails := make([]string, 0, len(candidates))
+	phones := make([]string, 0, len(candidates))
+	var inviteCodes, referralCodes []string
+	for _, candidate := range candidates {
+		emails = append(emails, candidate.req.Email)
+		phones = append(phones, candidate.req.Phone)
+		if code := candidate.req.InviteCode; code != "" {
+			inviteCodes = append(inviteCodes, code)
+		}
+		if code := candidate.req.ReferralCode; code != "" {
+			referralCodes = append(referralCodes, code)
+		}
+	}
+
+	var err error
+	if lookups.registeredEmails, err = s.repo.FindRegistered(ctx, pii.FieldEmail, emails); err != nil {
+		return nil, err
+	}
+	if lookups.registeredPhones, err = s.repo.FindRegistered(ctx, pii.FieldPhone, phones); err != nil {
+		return nil, err
+	}
+
+	if len(inviteCodes) > 0 {
+		codes, err := s.inviteCodeRepo.GetByCodes(ctx, inviteCodes)
+		if err != nil {
+			return nil, err
+		}
+		for i := range codes {
+			lookups.inviteCodes[codes[i].Code] = &codes[i]
+		}
+	}
+
+	if len(referralCodes) > 0 {
+		codes, err := s.referralRepo.GetByCodes(ctx, referralCodes)
+		if err != nil {
+			return nil, err
+		}
+		for i := range codes {
+			lookups.referralCodes[codes[i].Code] = &codes[i]
+		}
+	}
+
+	return lookups, nil
+}
+
+// prepare applies the checks prepareRegistration makes, in the same order,
+// against the resolved lookups, after rejecting rows that repeat an
+// earlier row's email or phone. An accepted row takes one use of its
+// invite code.
+func (l *importLookups) prepare(candidate importCandidate, seenEmails, seenPhones map[string]int) (*models.Registration, error) {
+	req := candidate.req
+	email := pii.Normalize(pii.FieldEmail, req.Email)
+	phone := pii.Normalize(pii.FieldPhone, req.Phone)
+
+	if prior, ok := seenEmails[email]; ok {
+		return nil, duplicateError("DUPLICATE_EMAIL", fmt.Sprintf("Email already used on row %d", prior))
+	}
+	if prior, ok := seenPhones[phone]; ok {
+		return nil, duplicateError("DUPLICATE_PHONE", fmt.Sprintf("Phone number already used on row %d", prior))
+	}
+
+	if l.registeredEmails[email] {
+		return nil, duplicateError("DUPLICATE_EMAIL", "Email already registered")
+	}
+	if l.registeredPhones[phone] {
+		return nil, duplicateError("DUPLICATE_PHONE", "Phone number already registered")
+	}
+
+	if req.InviteCode == "" && l.inviteOnly {
+		return nil, utils.NewForbiddenError("INVITE_CODE_REQUIRED", "Registration requires a valid invite code", nil)
+	}
+	if req.InviteCode != "" {
+		inviteCode := l.inviteCodes[req.InviteCode]
+		if err := inviteCodeError(inviteCode); err != nil {
+			return nil, err
+		}
+		if inviteCode.MaxUses != nil && inviteCode.UsedCount+l.inviteCodeUses[req.InviteCode] >= *inviteCode.MaxUses {
+			return nil, utils.NewBadRequestError("INVITE_CODE_EXHAUSTED", "Invite code's remaining uses are taken by earlier rows", nil)
+		}
+	}
+
+	if req.ReferralCode != "" {
+		if err := referralCodeError(l.referralCodes[req.ReferralCode]); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.InviteCode != "" {
+		l.inviteCodeUses[req.InviteCode]++
+	}
+	return newRegistration(req), nil
+}
+
+func readImportRows(file io.Reader, format string) ([][]string, error) {
+	switch format {
+	case ImportFormatCSV:
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, utils.NewBadRequestError("IMPORT_READ_ERROR", "Failed to read the uploaded file", err)
+		}
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, utils.NewBadRequestError("IMPORT_PARSE_ERROR", "The uploaded file is not valid CSV", err)
+		}
+		return rows, nil
+
+	case ImportFormatXLSX:
+		workbook, err := excelize.OpenReader(file)
+		if err != nil {
+			return nil, utils.NewBadRequestError("IMPORT_PARSE_ERROR", "The uploaded file is not a valid XLSX workbook", err)
+		}
+		defer workbook.Close()
+
+		sheets := workbook.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, nil
+		}
+		rows, err := workbook.GetRows(sheets[0])
+		if err != nil {
+			return nil, utils.NewBadRequestError("IMPORT_PARSE_ERROR", "Failed to read the first worksheet", err)
+		}
+		return rows, nil
+
+	default:
+		return nil, utils.NewBadRequestError("IMPORT_UNSUPPORTED_FORMAT", "Only CSV and XLSX files can be imported", nil)
+	}
+}
+
+// mapImportColumns resolves each header to a registration field, using the
+// caller's explicit mapping first and the known aliases otherwise. Columns
+// that match nothing (such as "ID" or "Created On") are ignored.
+func mapImportColumns(header []string, explicit map[string]string) (map[int]string, map[string]string, error) {
+	knownFields := make(map[string]bool, len(importHeaderAliases))
+	for _, field := range importHeaderAliases {
+		knownFields[field] = true
+	}
+
+	normalisedExplicit := make(map[string]string, len(explicit))
+	for column, field := range explicit {
+		if !knownFields[field] {
+			return nil, nil, utils.NewBadRequestError("IMPORT_INVALID_MAPPING", fmt.Sprintf("Unknown registration field %q in column mapping", field), nil)
+		}
+		normalisedExplicit[normaliseHeader(column)] = field
+	}
+
+	columns := make(map[int]string)
+	mapping := make(map[string]string)
+	mapped := make(map[string]bool)
+
+	for i, column := range header {
+		key := normaliseHeader(column)
+		field, ok := normalisedExplicit[key]
+		if !ok {
+			field, ok = importHeaderAliases[key]
+		}
+		if !ok || mapped[field] {
+			continue
+		}
+		columns[i] = field
+		mapping[column] = field
+		mapped[field] = true
+	}
+
+	var missing []string
+	for _, field := range importRequiredFields {
+		if !mapped[field] {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, nil, utils.NewBadRequestError("IMPORT_MISSING_COLUMNS", "Missing required columns: "+strings.Join(missing, ", "), nil)
+	}
+
+	return columns, mapping, nil
+}
+
+func buildImportRequest(row []string, columns map[int]string) *dto.CreateRegistrationRequest {
+	req := &dto.CreateRegistrationRequest{}
+	fields := map[string]*string{
+		"full_name":     &req.FullName,
+		"email":         &req.Email,
+		"phone":         &req.Phone,
+		"org_name":      &req.OrgName,
+		"designation":   &req.Designation,
+		"mkt_source":    &req.MktSource,
+		"food_pref":     &req.FoodPref,
+		"t_shirt":       &req.TShirt,
+		"utm_source":    &req.UTMSource,
+		"utm_medium":    &req.UTMMedium,
+		"utm_campaign":  &req.UTMCampaign,
+		"utm_term":      &req.UTMTerm,
+		"utm_content":   &req.UTMContent,
+		"referrer":      &req.Referrer,
+		"referral_code": &req.ReferralCode,
+		"invite_code":   &req.InviteCode,
+	}
+
+	for i, field := range columns {
+		if i < len(row) {
+			*fields[field] = strings.TrimSpace(row[i])
+		}
+	}
+
+	return req
+}
+
+func normaliseHeader(header string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(header) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func importRowError(rowNumber int, err error) dto.ImportRowError {
+	rowErr := dto.ImportRowError{Row: rowNumber, Code: "INVALID_ROW"}
+
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) {
+		rowErr.Errors = []utils.ValidationError{{Message: err.Error()}}
+		return rowErr
+	}
+
+	rowErr.Code = appErr.Code
+	if len(appErr.ValidationErrors) > 0 {
+		rowErr.Errors = appErr.ValidationErrors
+		return rowErr
+	}
+
+	rowErr.Errors = []utils.ValidationError{{Field: batchErrorFields[appErr.Code], Message: appErr.Message}}
+	return rowErr
+}
+
+func flattenImportErrors(rowErrors []dto.ImportRowError) []utils.ValidationError {
+	var validationErrors []utils.ValidationError
+	for _, rowErr := range rowErrors {
+		for _, ve := range rowErr.Errors {
+			field := fmt.Sprintf("row[%d]", rowErr.Row)
+			if ve.Field != "" {
+				field += "." + ve.Field
+			}
+			validationErrors = append(validationErrors, utils.ValidationError{Field: field, Message: ve.Message})
+		}
+	}
+	return validationErrors
+}
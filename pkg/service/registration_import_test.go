@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+const importHeader = "Full Name,Email,Phone,Food Preference,T-Shirt Size,Invite Code\n"
+
+func importCSV(rows ...string) *strings.Reader {
+	return strings.NewReader(importHeader + strings.Join(rows, "\n") + "\n")
+}
+
+func TestImportDryRunReportsWithoutWriting(t *testing.T) {
+	svc, repos := newTestRegistrationService()
+
+	report, err := svc.ImportRegistrations(context.Background(), importCSV(
+		"Ada,ada@example.com,9876543210,veg,M,",
+		"Bob,bob@example.com,9876543211,veg,XS,",
+	), ImportOptions{Format: ImportFormatCSV, DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportRegistrations: %v", err)
+	}
+
+	if report.TotalRows != 2 || report.ValidRows != 1 || report.InvalidRows != 1 {
+		t.Errorf("total/valid/invalid = %d/%d/%d, want 2/1/1", report.TotalRows, report.ValidRows, report.InvalidRows)
+	}
+	if got := report.RowErrors[0]; got.Row != 3 || got.Code != "VALIDATION_ERROR" {
+		t.Errorf("row error = %+v, want row 3 VALIDATION_ERROR", got)
+	}
+	if repos.registrations.bulkCalls != 0 || report.Imported != 0 || len(report.RegistrationIDs) != 0 {
+		t.Errorf("dry run wrote registrations")
+	}
+}
+
+func TestImportWithInvalidRowsFailsUnlessSkipping(t *testing.T) {
+	file := func() *strings.Reader {
+		return importCSV(
+			"Ada,ada@example.com,9876543210,veg,M,",
+			"Bob,bob@example.com,9876543211,veg,XS,",
+		)
+	}
+
+	svc, repos := newTestRegistrationService()
+	_, err := svc.ImportRegistrations(context.Background(), file(), ImportOptions{Format: ImportFormatCSV})
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "IMPORT_VALIDATION_ERROR" {
+		t.Fatalf("got %v, want IMPORT_VALIDATION_ERROR", err)
+	}
+	if got := appErr.ValidationErrors[0].Field; got != "row[3].t_shirt" {
+		t.Errorf("field = %q, want row[3].t_shirt", got)
+	}
+	if repos.registrations.bulkCalls != 0 {
+		t.Errorf("import with invalid rows wrote registrations")
+	}
+
+	report, err := svc.ImportRegistrations(context.Background(), file(), ImportOptions{Format: ImportFormatCSV, SkipInvalid: true})
+	if err != nil {
+		t.Fatalf("ImportRegistrations with skip_invalid: %v", err)
+	}
+	if report.Imported != 1 || len(report.RegistrationIDs) != 1 || report.InvalidRows != 1 {
+		t.Errorf("imported/ids/invalid = %d/%d/%d, want 1/1/1", report.Imported, len(report.RegistrationIDs), report.InvalidRows)
+	}
+}
+
+func TestImportRejectsDuplicatesWithinFileAndDatabase(t *testing.T) {
+	svc, repos := newTestRegistrationService()
+	repos.registrations.registrations = append(repos.registrations.registrations, &models.Registration{ID: 7, Email: "taken@example.com", Phone: "9000000000"})
+
+	report, err := svc.ImportRegistrations(context.Background(), importCSV(
+		"Ada,ada@example.com,9876543210,veg,M,",
+		"Ada Again, ADA@Example.com ,9876543219,veg,M,",
+		"Cy,TAKEN@example.com,9876543212,veg,M,",
+		"Di,di@example.com,9000000000,veg,M,",
+	), ImportOptions{Format: ImportFormatCSV, DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportRegistrations: %v", err)
+	}
+
+	want := map[int]string{3: "DUPLICATE_EMAIL", 4: "DUPLICATE_EMAIL", 5: "DUPLICATE_PHONE"}
+	if len(report.RowErrors) != len(want) {
+		t.Fatalf("row errors = %+v, want rows 3, 4 and 5", report.RowErrors)
+	}
+	for _, rowErr := range report.RowErrors {
+		if want[rowErr.Row] != rowErr.Code {
+			t.Errorf("row %d code = %q, want %q", rowErr.Row, rowErr.Code, want[rowErr.Row])
+		}
+	}
+}
+
+func TestImportResolvesLookupsOncePerKind(t *testing.T) {
+	svc, repos := newTestRegistrationService()
+	repos.inviteCodes.codes["VIP"] = &models.InviteCode{Code: "VIP", Active: true}
+
+	var rows []string
+	for i := 0; i < 20; i++ {
+		rows = append(rows, fmt.Sprintf("Person,p%d@example.com,98765432%02d,veg,M,vip", i, i))
+	}
+
+	report, err := svc.ImportRegistrations(context.Background(), importCSV(rows...), ImportOptions{Format: ImportFormatCSV})
+	if err != nil {
+		t.Fatalf("ImportRegistrations: %v", err)
+	}
+	if report.Imported != 20 {
+		t.Fatalf("imported %d rows, want 20: %+v", report.Imported, report.RowErrors)
+	}
+	if got := repos.registrations.lookupCalls; got != 2 {
+		t.Errorf("registration lookups = %d, want one for emails and one for phones", got)
+	}
+	if got := repos.inviteCodes.lookupCalls; got != 1 {
+		t.Errorf("invite code lookups = %d, want 1", got)
+	}
+}
+
+func TestImportRejectsRowsPastAnInviteCodesRemainingUses(t *testing.T) {
+	maxUses := 3
+	file := func() *strings.Reader {
+		return importCSV(
+			"Ada,ada@example.com,9876543210,veg,M,VIP",
+			"Bob,bob@example.com,9876543211,veg,M,VIP",
+			"Cy,cy@example.com,9876543212,veg,M,VIP",
+			"Di,di@example.com,9876543213,veg,M,",
+		)
+	}
+	svc, repos := newTestRegistrationService()
+	repos.inviteCodes.codes["VIP"] = &models.InviteCode{Code: "VIP", Active: true, MaxUses: &maxUses, UsedCount: 1}
+
+	report, err := svc.ImportRegistrations(context.Background(), file(), ImportOptions{Format: ImportFormatCSV, DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportRegistrations: %v", err)
+	}
+	if report.ValidRows != 3 || len(report.RowErrors) != 1 {
+		t.Fatalf("valid rows = %d, row errors = %+v; want 3 and one error", report.ValidRows, report.RowErrors)
+	}
+	if got := report.RowErrors[0]; got.Row != 4 || got.Code != "INVITE_CODE_EXHAUSTED" {
+		t.Errorf("row error = %+v, want row 4 INVITE_CODE_EXHAUSTED", got)
+	}
+
+	report, err = svc.ImportRegistrations(context.Background(), file(), ImportOptions{Format: ImportFormatCSV, SkipInvalid: true})
+	if err != nil {
+		t.Fatalf("ImportRegistrations with skip_invalid: %v", err)
+	}
+	if report.Imported != 3 || report.InvalidRows != 1 {
+		t.Errorf("imported/invalid = %d/%d, want 3/1", report.Imported, report.InvalidRows)
+	}
+}
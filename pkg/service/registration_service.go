@@ -2,30 +2,116 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/metrics"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/tracing"
 	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
 )
 
 type RegistrationService interface {
-	CreateRegistration(req *dto.CreateRegistrationRequest) (*dto.RegistrationResponse, error)
-	GenerateCSV() ([]byte, error)
+	CreateRegistration(ctx context.Context, req *dto.CreateRegistrationRequest) (*dto.RegistrationResponse, error)
+	GenerateCSV(ctx context.Context) ([]byte, error)
+	GetStats(ctx context.Context) (*dto.RegistrationStatsResponse, error)
+	CreateBatchRegistration(ctx context.Context, req *dto.BatchRegistrationRequest) (*dto.BatchRegistrationResponse, error)
+	ImportRegistrations(ctx context.Context, file io.Reader, opts ImportOptions) (*dto.ImportReportResponse, error)
 }
 
 type registrationService struct {
-	repo repository.RegistrationRepository
+	repo           repository.RegistrationRepository
+	referralRepo   repository.ReferralRepository
+	inviteCodeRepo repository.InviteCodeRepository
+	inviteOnly     bool
+	statsCache     statsCache
 }
 
-func NewRegistrationService(repo repository.RegistrationRepository) RegistrationService {
-	return &registrationService{repo: repo}
+type statsCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	data      *dto.RegistrationStatsResponse
+	expiresAt time.Time
 }
 
-func (s *registrationService) CreateRegistration(req *dto.CreateRegistrationRequest) (*dto.RegistrationResponse, error) {
+func (c *statsCache) get() *dto.RegistrationStatsResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.data != nil && time.Now().Before(c.expiresAt) {
+		return c.data
+	}
+	return nil
+}
+
+// put stores stats fetched at fetchedAt unless the cache already holds a
+// result from a later fetch.
+func (c *statsCache) put(data *dto.RegistrationStatsResponse, fetchedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := fetchedAt.Add(c.ttl)
+	if c.data != nil && !expiresAt.After(c.expiresAt) {
+		return
+	}
+	c.data = data
+	c.expiresAt = expiresAt
+}
+
+func NewRegistrationService(
+	repo repository.RegistrationRepository,
+	referralRepo repository.ReferralRepository,
+	inviteCodeRepo repository.InviteCodeRepository,
+) RegistrationService {
+	ttl, err := time.ParseDuration(config.GetEnv("STATS_CACHE_TTL", "30s"))
+	if err != nil || ttl < 0 {
+		ttl = 30 * time.Second
+	}
+	return &registrationService{
+		repo:           repo,
+		referralRepo:   referralRepo,
+		inviteCodeRepo: inviteCodeRepo,
+		inviteOnly:     config.GetEnv("INVITE_ONLY", "false") == "true",
+		statsCache:     statsCache{ttl: ttl},
+	}
+}
+
+func (s *registrationService) CreateRegistration(ctx context.Context, req *dto.CreateRegistrationRequest) (*dto.RegistrationResponse, error) {
+	ctx, span := tracing.Start(ctx, "RegistrationService.CreateRegistration")
+	defer span.End()
+
+	registration, err := s.prepareRegistration(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	createdReg, err := s.repo.Create(ctx, registration)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.RegistrationsCreated.WithLabelValues(metrics.SourceSingle).Inc()
+
+	return toRegistrationResponse(createdReg), nil
+}
+
+// prepareRegistration applies every check a registration must pass before
+// it is inserted and builds the row to store.
+func (s *registrationService) prepareRegistration(ctx context.Context, req *dto.CreateRegistrationRequest) (*models.Registration, error) {
+	ctx, span := tracing.Start(ctx, "RegistrationService.prepareRegistration")
+	defer span.End()
+
 	if validationErrors := req.Validate(); len(validationErrors) > 0 {
 		return nil, &utils.AppError{
 			HTTPCode:         400,
@@ -35,48 +121,104 @@ func (s *registrationService) CreateRegistration(req *dto.CreateRegistrationRequ
 		}
 	}
 
-	existingReg, err := s.repo.GetByEmail(req.Email)
-	if err == nil && existingReg != nil {
-		return nil, utils.NewBadRequestError("DUPLICATE_EMAIL", "Email already registered", nil)
+	// Only "not found" clears a duplicate check; a failed lookup must not
+	// let the registration through.
+	if _, err := s.repo.GetByEmail(ctx, req.Email); err == nil {
+		return nil, duplicateError("DUPLICATE_EMAIL", "Email already registered")
+	} else if !errors.Is(err, utils.ErrNotFound) {
+		return nil, err
 	}
 
-	existingRegByPhone, err := s.repo.GetByPhone(req.Phone)
-	if err == nil && existingRegByPhone != nil {
-		return nil, utils.NewBadRequestError("DUPLICATE_PHONE", "Phone number already registered", nil)
+	if _, err := s.repo.GetByPhone(ctx, req.Phone); err == nil {
+		return nil, duplicateError("DUPLICATE_PHONE", "Phone number already registered")
+	} else if !errors.Is(err, utils.ErrNotFound) {
+		return nil, err
 	}
 
-	registration := &models.Registration{
-		FullName:    req.FullName,
-		Email:       req.Email,
-		Phone:       req.Phone, 
-		OrgName:     req.OrgName,
-		Designation: req.Designation,
-		MktSource:   req.MktSource,
-		FoodPref:    req.FoodPref,
-		TShirt:      req.TShirt,
+	req.InviteCode = dto.NormalizeCode(req.InviteCode)
+	if req.InviteCode == "" && s.inviteOnly {
+		return nil, utils.NewForbiddenError("INVITE_CODE_REQUIRED", "Registration requires a valid invite code", nil)
+	}
+	if req.InviteCode != "" {
+		if err := checkInviteCode(ctx, s.inviteCodeRepo, req.InviteCode); err != nil {
+			return nil, err
+		}
 	}
 
-	createdReg, err := s.repo.Create(registration)
-	if err != nil {
-		return nil, err
+	if req.ReferralCode != "" {
+		req.ReferralCode = dto.NormalizeCode(req.ReferralCode)
+		code, err := s.referralRepo.GetByCode(ctx, req.ReferralCode)
+		if err != nil && !errors.Is(err, utils.ErrNotFound) {
+			return nil, err
+		}
+		if err := referralCodeError(code); err != nil {
+			return nil, err
+		}
 	}
 
+	return newRegistration(req), nil
+}
+
+// referralCodeError reports why a referral code that has already been
+// looked up cannot be used; nil means the code does not exist.
+func referralCodeError(code *models.ReferralCode) error {
+	if code == nil || !code.Active {
+		return utils.NewBadRequestError("INVALID_REFERRAL_CODE", "Referral code is invalid or inactive", nil)
+	}
+	return nil
+}
+
+func newRegistration(req *dto.CreateRegistrationRequest) *models.Registration {
+	return &models.Registration{
+		FullName:     req.FullName,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		OrgName:      req.OrgName,
+		Designation:  req.Designation,
+		MktSource:    req.MktSource,
+		FoodPref:     req.FoodPref,
+		TShirt:       req.TShirt,
+		UTMSource:    strings.TrimSpace(req.UTMSource),
+		UTMMedium:    strings.TrimSpace(req.UTMMedium),
+		UTMCampaign:  strings.TrimSpace(req.UTMCampaign),
+		UTMTerm:      strings.TrimSpace(req.UTMTerm),
+		UTMContent:   strings.TrimSpace(req.UTMContent),
+		Referrer:     strings.TrimSpace(req.Referrer),
+		ReferralCode: req.ReferralCode,
+		InviteCode:   req.InviteCode,
+	}
+}
+
+func toRegistrationResponse(reg *models.Registration) *dto.RegistrationResponse {
 	return &dto.RegistrationResponse{
-		ID:          createdReg.ID,
-		FullName:    createdReg.FullName,
-		Email:       createdReg.Email,
-		Phone:       createdReg.Phone,
-		OrgName:     createdReg.OrgName,
-		Designation: createdReg.Designation,
-		MktSource:   createdReg.MktSource,
-		FoodPref:    createdReg.FoodPref,
-		TShirt:      createdReg.TShirt,
-		CreatedOn:   createdReg.CreatedOn.Format(time.RFC3339),
-	}, nil
-}
-
-func (s *registrationService) GenerateCSV() ([]byte, error) {
-	registrations, err := s.repo.GetAll()
+		ID:          reg.ID,
+		FullName:    reg.FullName,
+		Email:       reg.Email,
+		Phone:       reg.Phone,
+		OrgName:     reg.OrgName,
+		Designation: reg.Designation,
+		MktSource:   reg.MktSource,
+		FoodPref:    reg.FoodPref,
+		TShirt:      reg.TShirt,
+		InviteCode:  reg.InviteCode,
+		Attribution: dto.Attribution{
+			UTMSource:    reg.UTMSource,
+			UTMMedium:    reg.UTMMedium,
+			UTMCampaign:  reg.UTMCampaign,
+			UTMTerm:      reg.UTMTerm,
+			UTMContent:   reg.UTMContent,
+			Referrer:     reg.Referrer,
+			ReferralCode: reg.ReferralCode,
+		},
+		CreatedOn: reg.CreatedOn.Format(time.RFC3339),
+	}
+}
+
+func (s *registrationService) GenerateCSV(ctx context.Context) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, "RegistrationService.GenerateCSV")
+	defer span.End()
+
+	registrations, err := s.repo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +236,14 @@ func (s *registrationService) GenerateCSV() ([]byte, error) {
 		"Marketing Source",
 		"Food Preference",
 		"t_shirt Size",
+		"UTM Source",
+		"UTM Medium",
+		"UTM Campaign",
+		"UTM Term",
+		"UTM Content",
+		"Referrer",
+		"Referral Code",
+		"Invite Code",
 		"Created On",
 	}
 	if err := writer.Write(header); err != nil {
@@ -105,12 +255,20 @@ func (s *registrationService) GenerateCSV() ([]byte, error) {
 			strconv.Itoa(reg.ID),
 			reg.FullName,
 			reg.Email,
-			reg.Phone, 
+			reg.Phone,
 			reg.OrgName,
 			reg.Designation,
 			reg.MktSource,
 			reg.FoodPref,
 			reg.TShirt,
+			reg.UTMSource,
+			reg.UTMMedium,
+			reg.UTMCampaign,
+			reg.UTMTerm,
+			reg.UTMContent,
+			reg.Referrer,
+			reg.ReferralCode,
+			reg.InviteCode,
 			reg.CreatedOn.Format("2006-01-02 15:04:05"),
 		}
 		if err := writer.Write(record); err != nil {
@@ -125,3 +283,249 @@ func (s *registrationService) GenerateCSV() ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+func (s *registrationService) GetStats(ctx context.Context) (*dto.RegistrationStatsResponse, error) {
+	ctx, span := tracing.Start(ctx, "RegistrationService.GetStats")
+	defer span.End()
+
+	if cached := s.statsCache.get(); cached != nil {
+		return cached, nil
+	}
+
+	// The lock is not held during the query, so a slow fetch never blocks
+	// readers; concurrent misses each query and the newest result is kept.
+	fetchedAt := time.Now()
+	stats, err := s.repo.GetStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.RegistrationStatsResponse{
+		Total:       stats.Total,
+		ByFoodPref:  toCountEntries(stats.ByFoodPref),
+		ByTShirt:    toCountEntries(stats.ByTShirt),
+		ByMktSource: toCountEntries(stats.ByMktSource),
+		ByOrgName:   toCountEntries(stats.ByOrgName),
+		PerDay:      toPeriodCountEntries(stats.PerDay),
+		PerHour:     toPeriodCountEntries(stats.PerHour),
+		GeneratedOn: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	s.statsCache.put(response, fetchedAt)
+
+	return response, nil
+}
+
+func toCountEntries(counts []models.FieldCount) []dto.CountEntry {
+	entries := make([]dto.CountEntry, 0, len(counts))
+	for _, c := range counts {
+		entries = append(entries, dto.CountEntry{Value: c.Value, Count: c.Count})
+	}
+	return entries
+}
+
+func toPeriodCountEntries(counts []models.PeriodCount) []dto.PeriodCountEntry {
+	entries := make([]dto.PeriodCountEntry, 0, len(counts))
+	for _, c := range counts {
+		entries = append(entries, dto.PeriodCountEntry{Period: c.Period.UTC().Format(time.RFC3339), Count: c.Count})
+	}
+	return entries
+}
+
+// batchErrorFields maps item-level error codes to the request field that
+// caused them, so atomic batch failures point at the offending entry.
+var batchErrorFields = map[string]string{
+	"DUPLICATE_EMAIL":         "email",
+	"DUPLICATE_PHONE":         "phone",
+	"INVALID_REFERRAL_CODE":   "referral_code",
+	"INVALID_INVITE_CODE":     "invite_code",
+	"INVITE_CODE_EXPIRED":     "invite_code",
+	"INVITE_CODE_EXHAUSTED":   "invite_code",
+	"INVITE_CODE_UNAVAILABLE": "invite_code",
+	"INVITE_CODE_REQUIRED":    "invite_code",
+}
+
+func (s *registrationService) CreateBatchRegistration(ctx context.Context, req *dto.BatchRegistrationRequest) (*dto.BatchRegistrationResponse, error) {
+	ctx, span := tracing.Start(ctx, "RegistrationService.CreateBatchRegistration")
+	defer span.End()
+
+	if validationErrors := req.Validate(); len(validationErrors) > 0 {
+		return nil, &utils.AppError{
+			HTTPCode:         400,
+			Code:             "VALIDATION_ERROR",
+			Message:          "Invalid input data",
+			ValidationErrors: validationErrors,
+		}
+	}
+
+	mode := dto.BatchModeAtomic
+	if req.IsPartial() {
+		mode = dto.BatchModePartial
+	}
+
+	registrations := make([]*models.Registration, len(req.Registrations))
+	itemErrs := make([]error, len(req.Registrations))
+	seenEmails := make(map[string]int)
+	seenPhones := make(map[string]int)
+
+	for i := range req.Registrations {
+		item := &req.Registrations[i]
+
+		if validationErrors := dto.ValidateStruct(item); len(validationErrors) > 0 {
+			itemErrs[i] = &utils.AppError{
+				HTTPCode:         400,
+				Code:             "VALIDATION_ERROR",
+				Message:          "Invalid input data",
+				ValidationErrors: validationErrors,
+			}
+			continue
+		}
+
+		email := pii.Normalize(pii.FieldEmail, item.Email)
+		phone := pii.Normalize(pii.FieldPhone, item.Phone)
+		if prior, ok := seenEmails[email]; ok {
+			itemErrs[i] = duplicateError("DUPLICATE_EMAIL", fmt.Sprintf("Email already used by registration %d in this batch", prior))
+			continue
+		}
+		if prior, ok := seenPhones[phone]; ok {
+			itemErrs[i] = duplicateError("DUPLICATE_PHONE", fmt.Sprintf("Phone number already used by registration %d in this batch", prior))
+			continue
+		}
+		seenEmails[email] = i
+		seenPhones[phone] = i
+
+		registration, err := s.prepareRegistration(ctx, item)
+		if err != nil {
+			if isServerError(err) {
+				return nil, err
+			}
+			itemErrs[i] = err
+			continue
+		}
+		registrations[i] = registration
+	}
+
+	if err := batchFailure(itemErrs, mode == dto.BatchModeAtomic); err != nil {
+		return nil, err
+	}
+
+	group := &models.RegistrationGroup{Name: strings.TrimSpace(req.GroupName)}
+	repoErrs, err := s.repo.CreateBatch(ctx, group, registrations, req.PrimaryContactIndex, mode == dto.BatchModePartial)
+	if err != nil {
+		return nil, err
+	}
+	for i, repoErr := range repoErrs {
+		if repoErr != nil {
+			if isServerError(repoErr) {
+				return nil, repoErr
+			}
+			itemErrs[i] = repoErr
+		}
+	}
+
+	if err := batchFailure(itemErrs, mode == dto.BatchModeAtomic); err != nil {
+		return nil, err
+	}
+
+	response := &dto.BatchRegistrationResponse{
+		GroupName:             group.Name,
+		PrimaryRegistrationID: group.PrimaryRegistrationID,
+		Mode:                  mode,
+		Total:                 len(req.Registrations),
+		Results:               make([]dto.BatchItemResult, 0, len(req.Registrations)),
+	}
+	if group.ID != 0 {
+		response.GroupID = &group.ID
+	}
+
+	for i := range req.Registrations {
+		if itemErrs[i] != nil {
+			response.Failed++
+			result := dto.BatchItemResult{Index: i, Status: "FAILED", Message: itemErrs[i].Error()}
+			var appErr *utils.AppError
+			if errors.As(itemErrs[i], &appErr) {
+				result.Code = appErr.Code
+				result.Errors = appErr.ValidationErrors
+			}
+			response.Results = append(response.Results, result)
+			continue
+		}
+		response.Succeeded++
+		response.Results = append(response.Results, dto.BatchItemResult{
+			Index:        i,
+			Status:       "CREATED",
+			Registration: toRegistrationResponse(registrations[i]),
+		})
+	}
+
+	metrics.RegistrationsCreated.WithLabelValues(metrics.SourceBatch).Add(float64(response.Succeeded))
+
+	return response, nil
+}
+
+// batchFailure returns the error for a batch that cannot be (or was not)
+// committed: any failed item in atomic mode, or every item failing in
+// partial mode. The per-item errors are reported as validation errors
+// whose field is prefixed with the item's index.
+func batchFailure(itemErrs []error, atomic bool) error {
+	var validationErrors []utils.ValidationError
+	failed := 0
+	for i, err := range itemErrs {
+		if err == nil {
+			continue
+		}
+		failed++
+		validationErrors = append(validationErrors, itemValidationErrors(i, err)...)
+	}
+
+	if failed == 0 || (!atomic && failed < len(itemErrs)) {
+		return nil
+	}
+
+	return &utils.AppError{
+		HTTPCode:         400,
+		Code:             "BATCH_VALIDATION_ERROR",
+		Message:          "One or more registrations in the batch are invalid",
+		ValidationErrors: validationErrors,
+	}
+}
+
+func itemValidationErrors(index int, err error) []utils.ValidationError {
+	prefix := fmt.Sprintf("registrations[%d]", index)
+
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) {
+		return []utils.ValidationError{{Field: prefix, Message: err.Error()}}
+	}
+
+	if len(appErr.ValidationErrors) > 0 {
+		validationErrors := make([]utils.ValidationError, 0, len(appErr.ValidationErrors))
+		for _, ve := range appErr.ValidationErrors {
+			validationErrors = append(validationErrors, utils.ValidationError{
+				Field:   prefix + "." + ve.Field,
+				Message: ve.Message,
+			})
+		}
+		return validationErrors
+	}
+
+	field := prefix
+	if name, ok := batchErrorFields[appErr.Code]; ok {
+		field += "." + name
+	}
+	return []utils.ValidationError{{Field: field, Message: appErr.Message}}
+}
+
+// duplicateError rejects a registration whose email or phone is already
+// taken, counting the rejection by code.
+func duplicateError(code, message string) error {
+	metrics.DuplicatesRejected.WithLabelValues(code).Inc()
+	return utils.NewDuplicateError(code, message, nil)
+}
+
+// isServerError reports whether err should abort the whole operation
+// rather than be attributed to one item. Cancelled requests count too.
+func isServerError(err error) bool {
+	var appErr *utils.AppError
+	return !errors.As(err, &appErr) || appErr.HTTPCode >= 500 || appErr.HTTPCode == utils.StatusClientClosedRequest
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+func registrationRequest(n int) dto.CreateRegistrationRequest {
+	return dto.CreateRegistrationRequest{
+		FullName: fmt.Sprintf("Person %d", n),
+		Email:    fmt.Sprintf("person%d@example.com", n),
+		Phone:    fmt.Sprintf("98765432%02d", n),
+		FoodPref: "veg",
+		TShirt:   "M",
+	}
+}
+
+func batchRequest(mode string, items ...dto.CreateRegistrationRequest) *dto.BatchRegistrationRequest {
+	return &dto.BatchRegistrationRequest{GroupName: "Team", Mode: mode, Registrations: items}
+}
+
+func TestBatchAtomicRejectsWholeBatchOnInvalidItem(t *testing.T) {
+	svc, repos := newTestRegistrationService()
+	bad := registrationRequest(2)
+	bad.TShirt = "XS"
+
+	_, err := svc.CreateBatchRegistration(context.Background(), batchRequest(dto.BatchModeAtomic, registrationRequest(1), bad))
+
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "BATCH_VALIDATION_ERROR" {
+		t.Fatalf("got %v, want BATCH_VALIDATION_ERROR", err)
+	}
+	if got := appErr.ValidationErrors[0].Field; got != "registrations[1].t_shirt" {
+		t.Errorf("field = %q, want registrations[1].t_shirt", got)
+	}
+	if repos.registrations.batchCalls != 0 || len(repos.registrations.registrations) != 0 {
+		t.Errorf("atomic batch reached the repository with an invalid item")
+	}
+}
+
+func TestBatchAtomicRollsBackWhenAnInsertFails(t *testing.T) {
+	svc, repos := newTestRegistrationService()
+	second := registrationRequest(2)
+	repos.registrations.insertErrs[second.Email] = utils.NewBadRequestError("INVITE_CODE_UNAVAILABLE", "Invite code is no longer available", nil)
+
+	_, err := svc.CreateBatchRegistration(context.Background(), batchRequest(dto.BatchModeAtomic, registrationRequest(1), second))
+
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "BATCH_VALIDATION_ERROR" {
+		t.Fatalf("got %v, want BATCH_VALIDATION_ERROR", err)
+	}
+	if len(repos.registrations.registrations) != 0 {
+		t.Errorf("kept %d registrations from a failed atomic batch", len(repos.registrations.registrations))
+	}
+}
+
+func TestBatchPartialKeepsValidItems(t *testing.T) {
+	svc, repos := newTestRegistrationService()
+	bad := registrationRequest(2)
+	bad.TShirt = "XS"
+
+	response, err := svc.CreateBatchRegistration(context.Background(), batchRequest(dto.BatchModePartial, registrationRequest(1), bad, registrationRequest(3)))
+	if err != nil {
+		t.Fatalf("CreateBatchRegistration: %v", err)
+	}
+
+	if response.Succeeded != 2 || response.Failed != 1 {
+		t.Errorf("succeeded/failed = %d/%d, want 2/1", response.Succeeded, response.Failed)
+	}
+	if got := response.Results[1]; got.Status != "FAILED" || got.Code != "VALIDATION_ERROR" {
+		t.Errorf("result[1] = %+v, want a failed VALIDATION_ERROR", got)
+	}
+	if response.GroupID == nil || response.PrimaryRegistrationID == nil {
+		t.Errorf("partial batch with created items has no group or primary contact")
+	}
+	if len(repos.registrations.registrations) != 2 {
+		t.Errorf("stored %d registrations, want 2", len(repos.registrations.registrations))
+	}
+}
+
+func TestBatchPartialWithNoValidItemsFails(t *testing.T) {
+	svc, _ := newTestRegistrationService()
+	bad := registrationRequest(1)
+	bad.TShirt = "XS"
+
+	_, err := svc.CreateBatchRegistration(context.Background(), batchRequest(dto.BatchModePartial, bad))
+
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "BATCH_VALIDATION_ERROR" {
+		t.Fatalf("got %v, want BATCH_VALIDATION_ERROR", err)
+	}
+}
+
+func TestBatchRejectsDuplicatesDifferingOnlyInCaseOrSpace(t *testing.T) {
+	svc, _ := newTestRegistrationService()
+	first := registrationRequest(1)
+	second := registrationRequest(2)
+	second.Email = " PERSON1@Example.com "
+
+	response, err := svc.CreateBatchRegistration(context.Background(), batchRequest(dto.BatchModePartial, first, second))
+	if err != nil {
+		t.Fatalf("CreateBatchRegistration: %v", err)
+	}
+
+	if got := response.Results[1]; got.Code != "DUPLICATE_EMAIL" {
+		t.Errorf("result[1] code = %q, want DUPLICATE_EMAIL", got.Code)
+	}
+}
+
+func TestDuplicateErrorsMatchErrConflict(t *testing.T) {
+	svc, _ := newTestRegistrationService()
+	req := registrationRequest(1)
+	if _, err := svc.CreateRegistration(context.Background(), &req); err != nil {
+		t.Fatalf("first registration: %v", err)
+	}
+
+	again := registrationRequest(1)
+	_, err := svc.CreateRegistration(context.Background(), &again)
+	if !errors.Is(err, utils.ErrConflict) {
+		t.Errorf("duplicate registration error %v does not match ErrConflict", err)
+	}
+}
+
+func TestStatsCacheKeepsTheNewestFetch(t *testing.T) {
+	cache := statsCache{ttl: time.Minute}
+	now := time.Now()
+	newer := &dto.RegistrationStatsResponse{Total: 2}
+	older := &dto.RegistrationStatsResponse{Total: 1}
+
+	cache.put(newer, now)
+	cache.put(older, now.Add(-time.Second))
+
+	if got := cache.get(); got != newer {
+		t.Errorf("cached total = %v, want the newer fetch", got)
+	}
+}
+
+func TestStatsCacheExpires(t *testing.T) {
+	cache := statsCache{ttl: time.Minute}
+	cache.put(&dto.RegistrationStatsResponse{Total: 1}, time.Now().Add(-2*time.Minute))
+
+	if got := cache.get(); got != nil {
+		t.Errorf("expired stats served: %+v", got)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/tracing"
+	"github.com/rs/zerolog/log"
+)
+
+type RetentionService interface {
+	Preview(ctx context.Context) (*dto.RetentionPreviewResponse, error)
+	Purge(ctx context.Context) (*models.RetentionPurgeResult, error)
+}
+
+type retentionService struct {
+	repo   repository.RetentionRepository
+	policy models.RetentionPolicy
+}
+
+func NewRetentionService(repo repository.RetentionRepository) RetentionService {
+	return &retentionService{repo: repo, policy: loadRetentionPolicy()}
+}
+
+// loadRetentionPolicy reads RETENTION_DAYS, RETENTION_ACTION and the
+// optional RETENTION_EVENT_END_DATE (YYYY-MM-DD). Retention is disabled
+// when RETENTION_DAYS is unset or any value is invalid. RETENTION_DAYS=0
+// is only accepted with an event end date; without one it would purge
+// every registration as soon as it was created.
+//
+// There is one policy per deployment, not per event: registrations carry
+// no event, so a deployment that serves several events applies the same
+// policy to all of them.
+func loadRetentionPolicy() models.RetentionPolicy {
+	policy := models.RetentionPolicy{
+		Action: config.GetEnv("RETENTION_ACTION", models.RetentionActionAnonymize),
+	}
+
+	daysValue := config.GetEnv("RETENTION_DAYS", "")
+	if daysValue == "" {
+		return policy
+	}
+
+	days, err := strconv.Atoi(daysValue)
+	if err != nil || days < 0 {
+		log.Warn().Str("value", daysValue).Msg("Invalid RETENTION_DAYS - retention disabled")
+		return policy
+	}
+	policy.RetentionDays = days
+
+	if policy.Action != models.RetentionActionAnonymize && policy.Action != models.RetentionActionDelete {
+		log.Warn().Str("value", policy.Action).Msg("Invalid RETENTION_ACTION - retention disabled")
+		return policy
+	}
+
+	if endDate := config.GetEnv("RETENTION_EVENT_END_DATE", ""); endDate != "" {
+		parsed, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			log.Warn().Str("value", endDate).Msg("Invalid RETENTION_EVENT_END_DATE - retention disabled")
+			return policy
+		}
+		policy.EventEndDate = &parsed
+	}
+
+	if days == 0 && policy.EventEndDate == nil {
+		log.Warn().Msg("RETENTION_DAYS=0 requires RETENTION_EVENT_END_DATE - retention disabled")
+		return policy
+	}
+
+	policy.Enabled = true
+	return policy
+}
+
+func (s *retentionService) Preview(ctx context.Context) (*dto.RetentionPreviewResponse, error) {
+	ctx, span := tracing.Start(ctx, "RetentionService.Preview")
+	defer span.End()
+
+	response := &dto.RetentionPreviewResponse{
+		Policy: dto.RetentionPolicyResponse{
+			Enabled:       s.policy.Enabled,
+			RetentionDays: s.policy.RetentionDays,
+			Action:        s.policy.Action,
+		},
+		RegistrationIDs: []int{},
+	}
+	if s.policy.EventEndDate != nil {
+		endDate := s.policy.EventEndDate.Format("2006-01-02")
+		response.Policy.EventEndDate = &endDate
+	}
+
+	cutoff, due := s.policy.Cutoff(time.Now())
+	if !due {
+		return response, nil
+	}
+
+	preview, err := s.repo.Preview(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoffValue := cutoff.UTC().Format(time.RFC3339)
+	response.Due = true
+	response.Cutoff = &cutoffValue
+	response.RegistrationsAffected = len(preview.RegistrationIDs)
+	response.RegistrationIDs = preview.RegistrationIDs
+	if preview.OldestCreatedOn != nil {
+		oldest := preview.OldestCreatedOn.UTC().Format(time.RFC3339)
+		response.OldestCreatedOn = &oldest
+	}
+	if preview.NewestCreatedOn != nil {
+		newest := preview.NewestCreatedOn.UTC().Format(time.RFC3339)
+		response.NewestCreatedOn = &newest
+	}
+
+	return response, nil
+}
+
+// Purge applies the policy once. It returns nil when nothing is due.
+func (s *retentionService) Purge(ctx context.Context) (*models.RetentionPurgeResult, error) {
+	ctx, span := tracing.Start(ctx, "RetentionService.Purge")
+	defer span.End()
+
+	cutoff, due := s.policy.Cutoff(time.Now())
+	if !due {
+		return nil, nil
+	}
+	return s.repo.Purge(ctx, cutoff, s.policy.Action)
+}
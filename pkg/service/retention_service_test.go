@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+)
+
+func TestLoadRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		days    string
+		endDate string
+		enabled bool
+	}{
+		{name: "unset"},
+		{name: "rolling window", days: "30", enabled: true},
+		{name: "negative days", days: "-1"},
+		{name: "zero days without an event end date", days: "0"},
+		{name: "zero days with an event end date", days: "0", endDate: "2026-06-01", enabled: true},
+		{name: "invalid event end date", days: "30", endDate: "01/06/2026"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RETENTION_DAYS", tt.days)
+			t.Setenv("RETENTION_EVENT_END_DATE", tt.endDate)
+			t.Setenv("RETENTION_ACTION", "anonymize")
+
+			if policy := loadRetentionPolicy(); policy.Enabled != tt.enabled {
+				t.Errorf("enabled = %v, want %v", policy.Enabled, tt.enabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// IdempotencyWorker deletes expired idempotency keys so the table does not
+// grow without bound. Reserve already ignores expired keys, so how often
+// this runs only affects table size.
+type IdempotencyWorker struct {
+	repo     repository.IdempotencyRepository
+	interval time.Duration
+	done     chan struct{}
+}
+
+func NewIdempotencyWorker(repo repository.IdempotencyRepository) *IdempotencyWorker {
+	interval, err := time.ParseDuration(config.GetEnv("IDEMPOTENCY_CLEANUP_INTERVAL", "10m"))
+	if err != nil || interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	return &IdempotencyWorker{
+		repo:     repo,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start deletes expired keys immediately and then every interval until
+// ctx is cancelled. Wait blocks until the loop has finished.
+func (w *IdempotencyWorker) Start(ctx context.Context) {
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.runOnce(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *IdempotencyWorker) Wait() {
+	<-w.done
+}
+
+func (w *IdempotencyWorker) runOnce(ctx context.Context) {
+	deleted, err := w.repo.DeleteExpired(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete expired idempotency keys")
+		return
+	}
+	if deleted > 0 {
+		log.Info().Int64("deleted", deleted).Msg("Expired idempotency keys deleted")
+	}
+}
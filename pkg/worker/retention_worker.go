@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/service"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const retentionActor = "system:retention"
+
+// Outcomes recorded in the metadata of retention.purge audit entries. A
+// purged run may have matched no registrations.
+const (
+	retentionOutcomePurged = "purged"
+	retentionOutcomeNotDue = "not_due"
+	retentionOutcomeFailed = "failed"
+)
+
+type RetentionWorker struct {
+	retention service.RetentionService
+	audit     service.AuditService
+	interval  time.Duration
+	done      chan struct{}
+}
+
+func NewRetentionWorker(retention service.RetentionService, audit service.AuditService) *RetentionWorker {
+	interval, err := time.ParseDuration(config.GetEnv("RETENTION_CHECK_INTERVAL", "1h"))
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+	return &RetentionWorker{
+		retention: retention,
+		audit:     audit,
+		interval:  interval,
+		done:      make(chan struct{}),
+	}
+}
+
+// Start runs a purge immediately and then every interval until ctx is
+// cancelled. Wait blocks until the loop, including any run in progress,
+// has finished.
+func (w *RetentionWorker) Start(ctx context.Context) {
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.runOnce(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *RetentionWorker) Wait() {
+	<-w.done
+}
+
+// runOnce applies the policy and audits the run whatever its outcome, so
+// the log shows the purge was attempted even when nothing was due, nothing
+// matched or the purge failed.
+func (w *RetentionWorker) runOnce(ctx context.Context) {
+	result, err := w.retention.Purge(ctx)
+
+	metadata := map[string]interface{}{
+		"registrations":           0,
+		"purged_cached_responses": int64(0),
+	}
+	switch {
+	case err != nil:
+		log.Error().Err(err).Msg("Retention purge failed")
+		metadata["outcome"] = retentionOutcomeFailed
+		metadata["error_code"] = "INTERNAL_ERROR"
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			metadata["error_code"] = appErr.Code
+		}
+	case result == nil:
+		metadata["outcome"] = retentionOutcomeNotDue
+	default:
+		log.Info().
+			Str("action", result.Action).
+			Int("registrations", len(result.RegistrationIDs)).
+			Int64("cached_responses", result.PurgedCachedResponses).
+			Msg("Retention purge completed")
+
+		metadata["outcome"] = retentionOutcomePurged
+		metadata["action"] = result.Action
+		metadata["cutoff"] = result.Cutoff.UTC().Format(time.RFC3339)
+		metadata["registrations"] = len(result.RegistrationIDs)
+		metadata["registration_ids"] = result.RegistrationIDs
+		metadata["purged_cached_responses"] = result.PurgedCachedResponses
+	}
+
+	entry := &models.AuditLog{
+		Actor:      retentionActor,
+		Action:     models.AuditActionRetentionPurge,
+		EntityType: "registration",
+		Changes:    map[string]models.FieldChange{},
+		Metadata:   metadata,
+	}
+	if err := w.audit.Record(context.WithoutCancel(ctx), entry); err != nil {
+		log.Error().Err(err).Msg("Failed to write retention audit log")
+	}
+}
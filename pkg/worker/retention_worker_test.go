@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/dto"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/models"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+type fakeRetentionService struct {
+	result *models.RetentionPurgeResult
+	err    error
+}
+
+func (f *fakeRetentionService) Preview(ctx context.Context) (*dto.RetentionPreviewResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeRetentionService) Purge(ctx context.Context) (*models.RetentionPurgeResult, error) {
+	return f.result, f.err
+}
+
+type fakeAuditService struct {
+	entries []*models.AuditLog
+}
+
+func (f *fakeAuditService) Record(ctx context.Context, entry *models.AuditLog) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeAuditService) ListAuditLogs(ctx context.Context, query *dto.AuditLogQuery) (*dto.AuditLogListResponse, error) {
+	return nil, nil
+}
+
+func TestRetentionRunIsAuditedWhateverTheOutcome(t *testing.T) {
+	cutoff := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		retention     *fakeRetentionService
+		outcome       string
+		registrations int
+	}{
+		{"not due", &fakeRetentionService{}, retentionOutcomeNotDue, 0},
+		{"nothing matched", &fakeRetentionService{result: &models.RetentionPurgeResult{
+			Action: models.RetentionActionAnonymize, Cutoff: cutoff, RegistrationIDs: []int{},
+		}}, retentionOutcomePurged, 0},
+		{"purged", &fakeRetentionService{result: &models.RetentionPurgeResult{
+			Action: models.RetentionActionDelete, Cutoff: cutoff, RegistrationIDs: []int{4, 7}, PurgedCachedResponses: 1,
+		}}, retentionOutcomePurged, 2},
+		{"failed", &fakeRetentionService{
+			err: utils.NewInternalServerError("DATABASE_ERROR", "Failed to purge registrations", errors.New("boom")),
+		}, retentionOutcomeFailed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &fakeAuditService{}
+			w := &RetentionWorker{retention: tt.retention, audit: audit}
+
+			w.runOnce(context.Background())
+
+			if len(audit.entries) != 1 {
+				t.Fatalf("audit entries = %d, want 1", len(audit.entries))
+			}
+			entry := audit.entries[0]
+			if entry.Action != models.AuditActionRetentionPurge || entry.Actor != retentionActor {
+				t.Errorf("entry = %s by %s", entry.Action, entry.Actor)
+			}
+			if entry.Metadata["outcome"] != tt.outcome {
+				t.Errorf("outcome = %v, want %s", entry.Metadata["outcome"], tt.outcome)
+			}
+			if entry.Metadata["registrations"] != tt.registrations {
+				t.Errorf("registrations = %v, want %d", entry.Metadata["registrations"], tt.registrations)
+			}
+		})
+	}
+}
+
+func TestFailedRetentionRunRecordsTheErrorCode(t *testing.T) {
+	audit := &fakeAuditService{}
+	w := &RetentionWorker{
+		retention: &fakeRetentionService{err: utils.NewInternalServerError("DATABASE_ERROR", "Failed to purge registrations", errors.New("boom"))},
+		audit:     audit,
+	}
+
+	w.runOnce(context.Background())
+
+	if got := audit.entries[0].Metadata["error_code"]; got != "DATABASE_ERROR" {
+		t.Errorf("error_code = %v", got)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog/log"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+// Re-encrypts registration PII under PII_ACTIVE_KEY_ID and fills in
+// missing blind indexes. Run it after adding a new key and switching the
+// active key ID; every key still referenced by stored rows must remain in
+// PII_ENCRYPTION_KEYS until it completes.
+func main() {
+	var batchSize int
+	var decrypt bool
+	flag.IntVar(&batchSize, "batch-size", 500, "Registrations rewritten per transaction")
+	flag.BoolVar(&decrypt, "decrypt", false, "Write PII back as plaintext (before rolling back the encryption migration)")
+	flag.Parse()
+
+	if err := godotenv.Load(".env"); err != nil {
+		log.Warn().Msg("Warning: .env file not found")
+	}
+
+	utils.InitLogger()
+
+	if batchSize <= 0 {
+		log.Fatal().Int("batch_size", batchSize).Msg("Batch size must be positive")
+	}
+
+	cipher, err := pii.NewCipherFromEnv()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid PII encryption configuration")
+	}
+
+	db := config.GetDBConnection()
+	defer config.CloseDBConnection()
+
+	repo := repository.NewPIIRotationRepository(db, cipher)
+
+	var updated int64
+	if decrypt {
+		updated, err = repo.Decrypt(context.Background(), batchSize)
+	} else {
+		updated, err = repo.Reencrypt(context.Background(), batchSize)
+	}
+	if err != nil {
+		log.Error().Err(err).Int64("updated", updated).Msg("PII rewrite failed")
+		config.CloseDBConnection()
+		os.Exit(1)
+	}
+
+	log.Info().Int64("updated", updated).Bool("decrypt", decrypt).Msg("PII rewrite complete")
+}
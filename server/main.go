@@ -1,20 +1,37 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/config"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/controller"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/live"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/metrics"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/middleware/bodylimit"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/middleware/cors"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/middleware/errorhandler"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/middleware/idempotency"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/middleware/logging"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/middleware/request_id"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/middleware/security"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/openapi"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/pii"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/repository"
 	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/service"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/tracing"
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/worker"
 	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
 )
 
@@ -25,47 +42,206 @@ func main() {
 
 	utils.InitLogger()
 
-	db := config.GetDBConnection()
-	defer config.CloseDBConnection()
-
-	registrationRepo := repository.NewRegistrationRepository(db)
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialise tracing")
+	}
 
-	registrationService := service.NewRegistrationService(registrationRepo)
+	piiCipher, err := pii.NewCipherFromEnv()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid PII encryption configuration")
+	}
 
-	registrationController := controller.NewRegistrationController(registrationService)
+	spec, err := openapi.Load(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid OpenAPI specification")
+	}
 
-	router := gin.Default()
+	db := config.GetDBConnection()
 
+	registrationRepo := repository.NewRegistrationRepository(db, piiCipher)
+	referralRepo := repository.NewReferralRepository(db)
+	inviteCodeRepo := repository.NewInviteCodeRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db, piiCipher)
+	auditRepo := repository.NewAuditRepository(db)
+	dataSubjectRepo := repository.NewDataSubjectRepository(db, piiCipher)
+	retentionRepo := repository.NewRetentionRepository(db, piiCipher)
+	healthRepo := repository.NewHealthRepository(db)
+
+	registrationService := service.NewRegistrationService(registrationRepo, referralRepo, inviteCodeRepo)
+	referralService := service.NewReferralService(referralRepo)
+	inviteCodeService := service.NewInviteCodeService(inviteCodeRepo)
+	auditService := service.NewAuditService(auditRepo)
+	dataSubjectService := service.NewDataSubjectService(dataSubjectRepo)
+	retentionService := service.NewRetentionService(retentionRepo)
+	healthService := service.NewHealthService(healthRepo)
+
+	registrationController := controller.NewRegistrationController(registrationService, auditService)
+	referralController := controller.NewReferralController(referralService, auditService)
+	inviteCodeController := controller.NewInviteCodeController(inviteCodeService, auditService)
+	auditController := controller.NewAuditController(auditService)
+	dataSubjectController := controller.NewDataSubjectController(dataSubjectService, auditService)
+	retentionController := controller.NewRetentionController(retentionService, auditService)
+	healthController := controller.NewHealthController(healthService)
+	errorCodeController := controller.NewErrorCodeController()
+
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+
+	liveBroker := live.NewBroker(db)
+	liveBroker.Start(backgroundCtx)
+	liveController := controller.NewLiveController(liveBroker)
+
+	retentionWorker := worker.NewRetentionWorker(retentionService, auditService)
+	retentionWorker.Start(backgroundCtx)
+
+	idempotencyWorker := worker.NewIdempotencyWorker(idempotencyRepo)
+	idempotencyWorker.Start(backgroundCtx)
+
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+
+	router.Use(otelgin.Middleware(tracing.ServiceName(), otelgin.WithFilter(tracedRequest)))
+	router.Use(metrics.Middleware())
 	router.Use(request_id.RequestIDMiddleware())
+	router.Use(logging.AccessLogMiddleware())
+	// Recovery sits inside the access log and metrics so panics are
+	// recorded as the 500 they turn into.
+	router.Use(errorhandler.RecoveryMiddleware())
+	router.Use(errorhandler.ErrorMiddleware())
+	router.Use(bodylimit.MaxBodySizeMiddleware(envBytes("MAX_REQUEST_BODY_BYTES", 1<<20), map[string]int64{
+		"/import": controller.ImportBodyLimit,
+	}))
 	router.Use(cors.SetupCORS())
 	router.Use(security.APIKeyAuthMiddleware())
+	if config.GetEnv("OPENAPI_VALIDATION", "true") == "true" {
+		router.Use(spec.ValidationMiddleware())
+	} else {
+		log.Warn().Msg("OPENAPI_VALIDATION is off; requests are not checked against the specification")
+	}
+
+	router.GET("/health", healthController.Health)
+	router.GET("/livez", healthController.Livez)
+	router.GET("/readyz", healthController.Readyz)
+
+	metrics.RegisterPool(db)
+	if metricsKey := os.Getenv("METRICS_API_KEY"); metricsKey != "" {
+		router.GET("/metrics", metrics.Handler(metricsKey))
+	} else {
+		log.Warn().Msg("METRICS_API_KEY is not set; /metrics is disabled")
+	}
 
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status": "healthy",
-		})
-	})
+	idempotent := idempotency.IdempotencyMiddleware(idempotencyRepo)
 
-	router.POST("/register", registrationController.Register)
+	router.POST("/register", idempotent, registrationController.Register)
+	router.POST("/register/batch", idempotent, registrationController.RegisterBatch)
 	router.GET("/download-csv", registrationController.DownloadCSV)
+	router.POST("/import", registrationController.Import)
+	router.GET("/stats", registrationController.Stats)
+	router.GET("/live", liveController.Stream)
+
+	router.POST("/referral-codes", referralController.CreateReferralCode)
+	router.GET("/referral-codes", referralController.ListReferralCodes)
+	router.GET("/attribution", referralController.AttributionReport)
+
+	router.POST("/invite-codes", inviteCodeController.CreateInviteCode)
+	router.GET("/invite-codes", inviteCodeController.ListInviteCodes)
+
+	router.GET("/audit-logs", auditController.ListAuditLogs)
+
+	router.POST("/data-subject/export", dataSubjectController.Export)
+	router.POST("/data-subject/erase", dataSubjectController.Erase)
 
-	router.NoRoute(func(c *gin.Context) {
-		requestID := utils.GetRequestID(c)
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":     "ERROR",
-			"code":       "ROUTE_NOT_FOUND",
-			"message":    "This route does not exist",
-			"request_id": requestID,
-		})
-	})
+	router.GET("/retention/preview", retentionController.Preview)
+
+	router.GET("/error-codes", errorCodeController.ListErrorCodes)
+
+	router.GET("/openapi.json", spec.Handler())
+	router.GET("/docs", spec.DocsHandler())
+
+	if problems := spec.CheckRoutes(router.Routes()); len(problems) > 0 {
+		log.Fatal().Strs("problems", problems).Msg("Routes and OpenAPI specification disagree")
+	}
+
+	router.NoRoute(errorhandler.NoRoute)
+	router.NoMethod(errorhandler.NoMethod)
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	log.Info().Str("port", port).Msg("Server starting")
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal().Err(err).Msg("Failed to start server")
+	drainPeriod := envDuration("SHUTDOWN_DRAIN_PERIOD", 10*time.Second)
+	shutdownTimeout := envDuration("SHUTDOWN_TIMEOUT", 30*time.Second)
+
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Info().Str("port", port).Msg("Server starting")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("Failed to start server")
+		}
+	}()
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	<-signalCtx.Done()
+	stopSignals()
+
+	log.Info().Dur("drain_period", drainPeriod).Msg("Shutdown signal received, draining")
+	// Readiness and /health fail from here on, so load balancers stop
+	// routing new traffic before we stop.
+	healthService.SetDraining()
+	server.SetKeepAlivesEnabled(false)
+	time.Sleep(drainPeriod)
+
+	// Live streams never go idle on their own, so end them before waiting
+	// for in-flight requests.
+	liveBroker.Close()
+	stopBackground()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("Server did not shut down cleanly")
+	}
+
+	retentionWorker.Wait()
+	idempotencyWorker.Wait()
+	config.CloseDBConnection()
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		log.Error().Err(err).Msg("Failed to flush traces")
+	}
+
+	log.Info().Msg("Server stopped")
+}
+
+// tracedRequest keeps probe and scrape traffic out of traces.
+func tracedRequest(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/health", "/livez", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(config.GetEnv(key, fallback.String()))
+	if err != nil || d < 0 {
+		return fallback
+	}
+	return d
+}
+
+func envBytes(key string, fallback int64) int64 {
+	n, err := strconv.ParseInt(config.GetEnv(key, strconv.FormatInt(fallback, 10)), 10, 64)
+	if err != nil || n <= 0 {
+		return fallback
 	}
+	return n
 }
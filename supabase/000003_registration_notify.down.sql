@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_registrations_notify ON registrations;
+DROP FUNCTION IF EXISTS notify_registration_created();
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_registrations_utm_source;
+DROP INDEX IF EXISTS idx_registrations_referral_code;
+
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS referral_code,
+    DROP COLUMN IF EXISTS referrer,
+    DROP COLUMN IF EXISTS utm_content,
+    DROP COLUMN IF EXISTS utm_term,
+    DROP COLUMN IF EXISTS utm_campaign,
+    DROP COLUMN IF EXISTS utm_medium,
+    DROP COLUMN IF EXISTS utm_source;
+
+DROP TABLE IF EXISTS referral_codes;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS referral_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    owner VARCHAR(255) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_on TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+ALTER TABLE registrations
+    ADD COLUMN utm_source VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN utm_medium VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN utm_campaign VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN utm_term VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN utm_content VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN referrer VARCHAR(2048) NOT NULL DEFAULT '',
+    ADD COLUMN referral_code VARCHAR(64) REFERENCES referral_codes(code);
+
+CREATE INDEX idx_registrations_referral_code ON registrations(referral_code);
+CREATE INDEX idx_registrations_utm_source ON registrations(utm_source);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_registrations_invite_code;
+
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS invite_code;
+
+DROP TABLE IF EXISTS invite_codes;
+
+COMMIT;
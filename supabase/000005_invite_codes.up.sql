@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS invite_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    label VARCHAR(255) NOT NULL DEFAULT '',
+    max_uses INTEGER CHECK (max_uses > 0),
+    used_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_on TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK (max_uses IS NULL OR used_count <= max_uses)
+);
+
+ALTER TABLE registrations
+    ADD COLUMN invite_code VARCHAR(64) REFERENCES invite_codes(code);
+
+CREATE INDEX idx_registrations_invite_code ON registrations(invite_code);
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_registrations_group_id;
+
+ALTER TABLE registration_groups
+    DROP CONSTRAINT IF EXISTS fk_registration_groups_primary;
+
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS registration_groups;
+
+COMMIT;
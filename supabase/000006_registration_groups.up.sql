@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS registration_groups (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    primary_registration_id INTEGER,
+    created_on TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+ALTER TABLE registrations
+    ADD COLUMN group_id INTEGER REFERENCES registration_groups(id) ON DELETE SET NULL;
+
+ALTER TABLE registration_groups
+    ADD CONSTRAINT fk_registration_groups_primary
+    FOREIGN KEY (primary_registration_id) REFERENCES registrations(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_registrations_group_id ON registrations(group_id);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_audit_logs_append_only ON audit_logs;
+DROP FUNCTION IF EXISTS prevent_audit_log_mutation();
+
+DROP INDEX IF EXISTS idx_audit_logs_action;
+DROP INDEX IF EXISTS idx_audit_logs_entity;
+DROP INDEX IF EXISTS idx_audit_logs_created_on;
+DROP TABLE IF EXISTS audit_logs;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS erased_on;
+
+COMMIT;
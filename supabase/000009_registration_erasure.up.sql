@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE registrations
+    ADD COLUMN erased_on TIMESTAMP WITH TIME ZONE;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_registrations_phone_bidx;
+DROP INDEX IF EXISTS idx_registrations_email_bidx;
+
+-- Rows must be decrypted before rolling back; ciphertexts do not fit the
+-- original column sizes.
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS phone_bidx,
+    DROP COLUMN IF EXISTS email_bidx,
+    ALTER COLUMN phone TYPE VARCHAR(13),
+    ALTER COLUMN email TYPE VARCHAR(255),
+    ALTER COLUMN full_name TYPE VARCHAR(255);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Ciphertexts are much longer than the plaintext they replace.
+ALTER TABLE registrations
+    ALTER COLUMN full_name TYPE TEXT,
+    ALTER COLUMN email TYPE TEXT,
+    ALTER COLUMN phone TYPE TEXT,
+    ADD COLUMN email_bidx VARCHAR(64),
+    ADD COLUMN phone_bidx VARCHAR(64);
+
+CREATE INDEX idx_registrations_email_bidx ON registrations(email_bidx);
+CREATE INDEX idx_registrations_phone_bidx ON registrations(phone_bidx);
+
+COMMIT;
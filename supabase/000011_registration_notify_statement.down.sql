@@ -0,0 +1,25 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_registrations_notify ON registrations;
+DROP FUNCTION IF EXISTS notify_registrations_created();
+
+CREATE FUNCTION notify_registration_created() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify(
+        'registration_events',
+        json_build_object(
+            'type', 'registration_created',
+            'id', NEW.id,
+            'created_on', NEW.created_on,
+            'total_registrations', (SELECT COUNT(*) FROM registrations)
+        )::text
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_registrations_notify
+    AFTER INSERT ON registrations
+    FOR EACH ROW EXECUTE FUNCTION notify_registration_created();
+
+COMMIT;
@@ -0,0 +1,53 @@
+BEGIN;
+
+-- One notification per INSERT statement instead of per row, without a
+-- table count, so COPY-based imports stay linear and do not flood the
+-- LISTEN connection. Subscribers that need totals call /stats.
+DROP TRIGGER IF EXISTS trg_registrations_notify ON registrations;
+DROP FUNCTION IF EXISTS notify_registration_created();
+
+CREATE FUNCTION notify_registrations_created() RETURNS TRIGGER AS $$
+DECLARE
+    inserted_count BIGINT;
+    first_id INT;
+    last_id INT;
+    single RECORD;
+BEGIN
+    SELECT COUNT(*), MIN(id), MAX(id) INTO inserted_count, first_id, last_id FROM inserted;
+
+    IF inserted_count = 0 THEN
+        RETURN NULL;
+    END IF;
+
+    IF inserted_count = 1 THEN
+        SELECT id, created_on INTO single FROM inserted;
+        PERFORM pg_notify(
+            'registration_events',
+            json_build_object(
+                'type', 'registration_created',
+                'id', single.id,
+                'created_on', single.created_on
+            )::text
+        );
+    ELSE
+        PERFORM pg_notify(
+            'registration_events',
+            json_build_object(
+                'type', 'registrations_created',
+                'count', inserted_count,
+                'first_id', first_id,
+                'last_id', last_id
+            )::text
+        );
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_registrations_notify
+    AFTER INSERT ON registrations
+    REFERENCING NEW TABLE AS inserted
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_registrations_created();
+
+COMMIT;
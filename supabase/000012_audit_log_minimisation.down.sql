@@ -0,0 +1,5 @@
+BEGIN;
+
+-- The removed addresses and designations cannot be restored.
+
+COMMIT;
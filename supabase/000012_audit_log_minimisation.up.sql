@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Existing entries were written with the full client address and the
+-- registrant's designation. Reduce them to what new entries keep; the
+-- append-only trigger is lifted for this one rewrite only.
+ALTER TABLE audit_logs DISABLE TRIGGER trg_audit_logs_append_only;
+
+UPDATE audit_logs
+SET ip_address = CASE
+        WHEN family(ip_address::inet) = 4 THEN network(set_masklen(ip_address::inet, 24))::text
+        ELSE network(set_masklen(ip_address::inet, 48))::text
+    END
+WHERE ip_address <> '' AND position('/' IN ip_address) = 0;
+
+UPDATE audit_logs
+SET changes = changes - 'designation'
+WHERE changes ? 'designation';
+
+ALTER TABLE audit_logs ENABLE TRIGGER trg_audit_logs_append_only;
+
+COMMIT;
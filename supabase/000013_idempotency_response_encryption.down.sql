@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Earlier versions would replay the ciphertext as the response body.
+DELETE FROM idempotency_keys WHERE subject_bidx IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_idempotency_keys_subject_bidx;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS subject_bidx;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Cached responses are now encrypted. subject_bidx holds the blind indexes
+-- of the emails and phones each one contains, so data-subject requests can
+-- still find them. Rows cached before this migration keep a NULL index and
+-- a plaintext body until they expire.
+ALTER TABLE idempotency_keys ADD COLUMN subject_bidx VARCHAR(64)[];
+
+CREATE INDEX idx_idempotency_keys_subject_bidx ON idempotency_keys USING GIN (subject_bidx);
+
+COMMIT;
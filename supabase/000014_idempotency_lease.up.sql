@@ -0,0 +1,11 @@
+BEGIN;
+
+-- A request holds its key only until locked_until, so a key left in
+-- progress by a crashed request can be taken over by a retry.
+ALTER TABLE idempotency_keys ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;
+
+UPDATE idempotency_keys
+SET locked_until = created_on + INTERVAL '5 minutes'
+WHERE status_code IS NULL;
+
+COMMIT;
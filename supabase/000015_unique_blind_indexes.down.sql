@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_registrations_phone_bidx_unique;
+DROP INDEX IF EXISTS idx_registrations_email_bidx_unique;
+
+CREATE INDEX idx_registrations_email_bidx ON registrations(email_bidx);
+CREATE INDEX idx_registrations_phone_bidx ON registrations(phone_bidx);
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- The duplicate checks in the service are check-then-insert, so only the
+-- database can stop two concurrent registrations with the same email or
+-- phone. Erased rows have no blind index and are left out anyway.
+-- This fails if live duplicates already exist; resolve them first.
+DROP INDEX IF EXISTS idx_registrations_email_bidx;
+DROP INDEX IF EXISTS idx_registrations_phone_bidx;
+
+CREATE UNIQUE INDEX idx_registrations_email_bidx_unique ON registrations(email_bidx) WHERE erased_on IS NULL;
+CREATE UNIQUE INDEX idx_registrations_phone_bidx_unique ON registrations(phone_bidx) WHERE erased_on IS NULL;
+
+COMMIT;
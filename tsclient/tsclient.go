@@ -0,0 +1,477 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rs/zerolog/log"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/openapi"
+	"github.com/iamsuteerth/tx-qr-tool-backend/utils"
+)
+
+// Generates a dependency-free TypeScript client from the embedded OpenAPI
+// document. The output is checked in and copied into the front-end; rerun
+// it whenever pkg/openapi/openapi.yaml changes. With -check it writes
+// nothing and exits non-zero if the checked-in file is stale.
+func main() {
+	var out string
+	var check bool
+	flag.StringVar(&out, "out", "clients/typescript/api.ts", "File to write the client to")
+	flag.BoolVar(&check, "check", false, "Fail if the file differs from a fresh generation instead of writing it")
+	flag.Parse()
+
+	utils.InitLogger()
+
+	spec, err := openapi.Load(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid OpenAPI specification")
+	}
+
+	source := generate(spec.Document())
+
+	if check {
+		current, err := os.ReadFile(out)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to read client")
+		}
+		if !bytes.Equal(current, source) {
+			log.Fatal().Str("out", out).Msg("TypeScript client is stale; run make openapi-client")
+		}
+		log.Info().Str("out", out).Msg("TypeScript client is up to date")
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		log.Fatal().Err(err).Msg("Failed to create output directory")
+	}
+	if err := os.WriteFile(out, source, 0o644); err != nil {
+		log.Fatal().Err(err).Msg("Failed to write client")
+	}
+
+	log.Info().Str("out", out).Msg("TypeScript client generated")
+}
+
+var methodOrder = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+func generate(doc *openapi3.T) []byte {
+	var b bytes.Buffer
+
+	b.WriteString("// Code generated by tsclient from pkg/openapi/openapi.yaml. DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "// %s %s\n\n", doc.Info.Title, doc.Info.Version)
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeSchema(&b, name, doc.Components.Schemas[name].Value)
+	}
+
+	b.WriteString(clientPrelude)
+
+	paths := doc.Paths.InMatchingOrder()
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := doc.Paths.Value(path)
+		for _, method := range methodOrder {
+			if op := item.GetOperation(method); op != nil {
+				writeOperation(&b, method, path, op)
+			}
+		}
+	}
+
+	return append(bytes.TrimRight(b.Bytes(), "\n"), "\n}\n"...)
+}
+
+func writeSchema(b *bytes.Buffer, name string, schema *openapi3.Schema) {
+	writeDoc(b, "", schema.Description)
+	if schema.Type.Is(openapi3.TypeObject) && len(schema.Properties) > 0 && !schema.Nullable {
+		fmt.Fprintf(b, "export interface %s %s\n\n", name, objectType(schema, ""))
+		return
+	}
+	fmt.Fprintf(b, "export type %s = %s;\n\n", name, tsType(openapi3.NewSchemaRef("", schema), ""))
+}
+
+// tsType renders a schema as a TypeScript type. Component references keep
+// their names; everything else is written inline.
+func tsType(ref *openapi3.SchemaRef, indent string) string {
+	if ref == nil || ref.Value == nil {
+		return "unknown"
+	}
+	if ref.Ref != "" {
+		return nullable(ref.Ref[strings.LastIndex(ref.Ref, "/")+1:], ref.Value)
+	}
+
+	schema := ref.Value
+	if len(schema.AllOf) > 0 {
+		parts := make([]string, 0, len(schema.AllOf))
+		for _, part := range schema.AllOf {
+			parts = append(parts, tsType(part, indent))
+		}
+		return nullable(strings.Join(parts, " & "), schema)
+	}
+
+	var t string
+	switch {
+	case len(schema.Enum) > 0:
+		values := make([]string, 0, len(schema.Enum))
+		for _, v := range schema.Enum {
+			values = append(values, literal(v))
+		}
+		t = strings.Join(values, " | ")
+	case schema.Type.Is(openapi3.TypeString):
+		t = "string"
+		if schema.Format == "binary" {
+			t = "Blob"
+		}
+	case schema.Type.Is(openapi3.TypeInteger), schema.Type.Is(openapi3.TypeNumber):
+		t = "number"
+	case schema.Type.Is(openapi3.TypeBoolean):
+		t = "boolean"
+	case schema.Type.Is(openapi3.TypeArray):
+		t = tsType(schema.Items, indent)
+		if strings.ContainsAny(t, "|&") {
+			t = "(" + t + ")"
+		}
+		t += "[]"
+	case schema.Type.Is(openapi3.TypeObject):
+		t = objectType(schema, indent)
+	default:
+		t = "unknown"
+	}
+	return nullable(t, schema)
+}
+
+func objectType(schema *openapi3.Schema, indent string) string {
+	if len(schema.Properties) == 0 {
+		if schema.AdditionalProperties.Schema != nil {
+			return "Record<string, " + tsType(schema.AdditionalProperties.Schema, indent) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	b.WriteString("{\n")
+	for _, name := range names {
+		prop := schema.Properties[name]
+		if prop.Ref == "" && prop.Value != nil {
+			writeDoc(&b, indent+"  ", prop.Value.Description)
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, propertyName(name), optional, tsType(prop, indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func nullable(t string, schema *openapi3.Schema) string {
+	if schema.Nullable {
+		return t + " | null"
+	}
+	return t
+}
+
+func literal(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+func propertyName(name string) string {
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return strconv.Quote(name)
+		}
+	}
+	return name
+}
+
+func writeDoc(b *bytes.Buffer, indent, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, text)
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s\n", strings.TrimRight(indent+" * "+line, " "))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+// writeOperation emits one client method. JSON bodies are passed as the
+// first argument, query and header parameters as an optional params
+// object, and the envelope's data is returned unwrapped.
+func writeOperation(b *bytes.Buffer, method, path string, op *openapi3.Operation) {
+	success, mediaType := successResponse(op)
+
+	doc := op.Summary
+	if op.Description != "" {
+		doc += "\n\n" + strings.TrimSpace(op.Description)
+	}
+	writeDoc(b, "  ", doc)
+
+	if mediaType == "text/event-stream" {
+		fmt.Fprintf(b, "  %sUrl(): string {\n", op.OperationID)
+		fmt.Fprintf(b, "    return this.url(%s, this.options.apiKey ? { api_key: this.options.apiKey } : {});\n", strconv.Quote(path))
+		b.WriteString("  }\n\n")
+		return
+	}
+
+	var args, query, headers []string
+	bodyKind := ""
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		content := op.RequestBody.Value.Content
+		switch {
+		case content.Get("application/json") != nil:
+			bodyKind = "json"
+			args = append(args, "body: "+tsType(content.Get("application/json").Schema, "  "))
+		case content.Get("multipart/form-data") != nil:
+			bodyKind = "form"
+			args = append(args, "body: "+tsType(content.Get("multipart/form-data").Schema, "  "))
+		}
+	}
+
+	var paramFields []string
+	for _, p := range op.Parameters {
+		param := p.Value
+		if param == nil || (param.In != openapi3.ParameterInQuery && param.In != openapi3.ParameterInHeader) {
+			continue
+		}
+		optional := "?"
+		if param.Required {
+			optional = ""
+		}
+		paramFields = append(paramFields, fmt.Sprintf("%s%s: %s", propertyName(param.Name), optional, tsType(param.Schema, "  ")))
+		if param.In == openapi3.ParameterInQuery {
+			query = append(query, param.Name)
+		} else {
+			headers = append(headers, param.Name)
+		}
+	}
+	if len(paramFields) > 0 {
+		args = append(args, "params: { "+strings.Join(paramFields, "; ")+" } = {}")
+	}
+
+	returnType, unwrap := resultType(success, mediaType)
+
+	fmt.Fprintf(b, "  async %s(%s): Promise<%s> {\n", op.OperationID, strings.Join(args, ", "), returnType)
+
+	queryExpr := "{}"
+	if len(query) > 0 {
+		fields := make([]string, 0, len(query))
+		for _, name := range query {
+			fields = append(fields, fmt.Sprintf("%s: params[%s]", propertyName(name), strconv.Quote(name)))
+		}
+		queryExpr = "{ " + strings.Join(fields, ", ") + " }"
+	}
+	headerExpr := "{}"
+	if len(headers) > 0 {
+		fields := make([]string, 0, len(headers))
+		for _, name := range headers {
+			fields = append(fields, fmt.Sprintf("%s: params[%s]", strconv.Quote(name), strconv.Quote(name)))
+		}
+		headerExpr = "{ " + strings.Join(fields, ", ") + " }"
+	}
+
+	bodyExpr := "undefined"
+	switch bodyKind {
+	case "json":
+		bodyExpr = "body"
+	case "form":
+		bodyExpr = "toFormData(body)"
+	}
+
+	responseKind := "json"
+	switch {
+	case mediaType == "":
+		responseKind = "none"
+	case mediaType != "application/json":
+		responseKind = "blob"
+	}
+
+	fmt.Fprintf(b, "    const result = await this.request(%s, %s, %s, %s, %s, %s);\n",
+		strconv.Quote(method), strconv.Quote(path), queryExpr, headerExpr, bodyExpr, strconv.Quote(responseKind))
+	if unwrap {
+		fmt.Fprintf(b, "    return (result as { data: %s }).data;\n", returnType)
+	} else {
+		fmt.Fprintf(b, "    return result as %s;\n", returnType)
+	}
+	b.WriteString("  }\n\n")
+}
+
+// successResponse returns the schema and media type of the first 2xx
+// response.
+func successResponse(op *openapi3.Operation) (*openapi3.SchemaRef, string) {
+	codes := make([]string, 0, op.Responses.Len())
+	for code := range op.Responses.Map() {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		response := op.Responses.Value(code).Value
+		if response == nil {
+			continue
+		}
+		for _, mediaType := range []string{"application/json", "text/csv", "text/event-stream"} {
+			if content := response.Content.Get(mediaType); content != nil {
+				return content.Schema, mediaType
+			}
+		}
+	}
+	return nil, ""
+}
+
+// resultType picks what a method resolves to: the envelope's data when
+// the response is enveloped, the body otherwise, or a Blob for files.
+func resultType(schema *openapi3.SchemaRef, mediaType string) (string, bool) {
+	switch mediaType {
+	case "":
+		return "void", false
+	case "application/json":
+	default:
+		return "Blob", false
+	}
+
+	if data := envelopeData(schema); data != nil {
+		return tsType(data, "  "), true
+	}
+	return tsType(schema, "  "), false
+}
+
+func envelopeData(ref *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if ref == nil || ref.Value == nil {
+		return nil
+	}
+	for _, part := range ref.Value.AllOf {
+		if part.Value == nil {
+			continue
+		}
+		if data, ok := part.Value.Properties["data"]; ok {
+			return data
+		}
+		if data := envelopeData(part); data != nil {
+			return data
+		}
+	}
+	return nil
+}
+
+const clientPrelude = `export interface ApiClientOptions {
+  /** Base URL of the API, without a trailing slash. */
+  baseUrl: string;
+  /** Sent as X-Api-Key on every request. */
+  apiKey?: string;
+  /** Defaults to the global fetch. */
+  fetch?: typeof fetch;
+}
+
+/** Thrown for every non-2xx response. */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly code: string;
+  readonly requestId: string | undefined;
+  readonly errors: ValidationError[];
+
+  constructor(status: number, body: Partial<ErrorResponse> | undefined) {
+    super(body?.message ?? ` + "`Request failed with status ${status}`" + `);
+    this.name = "ApiError";
+    this.status = status;
+    this.code = body?.code ?? "UNKNOWN_ERROR";
+    this.requestId = body?.request_id;
+    this.errors = body?.errors ?? [];
+  }
+}
+
+type QueryValue = string | number | boolean | undefined;
+
+function toFormData(body: object): FormData {
+  const form = new FormData();
+  for (const [key, value] of Object.entries(body)) {
+    if (value === undefined || value === null) continue;
+    form.append(key, value instanceof Blob ? value : String(value));
+  }
+  return form;
+}
+
+export class ApiClient {
+  private readonly options: ApiClientOptions;
+
+  constructor(options: ApiClientOptions) {
+    this.options = options;
+  }
+
+  private url(path: string, query: Record<string, QueryValue>): string {
+    const search = new URLSearchParams();
+    for (const [key, value] of Object.entries(query)) {
+      if (value !== undefined) search.append(key, String(value));
+    }
+    const qs = search.toString();
+    return this.options.baseUrl + path + (qs ? "?" + qs : "");
+  }
+
+  private async request(
+    method: string,
+    path: string,
+    query: Record<string, QueryValue>,
+    headers: Record<string, string | undefined>,
+    body: unknown,
+    responseKind: "json" | "blob" | "none",
+  ): Promise<unknown> {
+    const init: RequestInit = { method, headers: {} };
+    const requestHeaders = init.headers as Record<string, string>;
+    if (this.options.apiKey) requestHeaders["X-Api-Key"] = this.options.apiKey;
+    for (const [key, value] of Object.entries(headers)) {
+      if (value !== undefined) requestHeaders[key] = value;
+    }
+    if (body instanceof FormData) {
+      init.body = body;
+    } else if (body !== undefined) {
+      requestHeaders["Content-Type"] = "application/json";
+      init.body = JSON.stringify(body);
+    }
+
+    const doFetch = this.options.fetch ?? fetch;
+    const response = await doFetch(this.url(path, query), init);
+    if (!response.ok) {
+      const errorBody = await response.json().catch(() => undefined);
+      throw new ApiError(response.status, errorBody);
+    }
+    if (responseKind === "blob") return response.blob();
+    if (responseKind === "none") return undefined;
+    return response.json();
+  }
+
+`
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/iamsuteerth/tx-qr-tool-backend/pkg/openapi"
+)
+
+func TestCheckedInClientIsCurrent(t *testing.T) {
+	spec, err := openapi.Load(context.Background())
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+
+	current, err := os.ReadFile("../clients/typescript/api.ts")
+	if err != nil {
+		t.Fatalf("read client: %v", err)
+	}
+
+	if !bytes.Equal(current, generate(spec.Document())) {
+		t.Fatal("clients/typescript/api.ts is stale; run make openapi-client")
+	}
+}
@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const APIKeyNameContextKey = "api_key_name"
+
+func GetActor(ctx *gin.Context) string {
+	if name := ctx.GetString(APIKeyNameContextKey); name != "" {
+		return "api_key:" + name
+	}
+	return "anonymous"
+}
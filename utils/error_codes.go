@@ -0,0 +1,73 @@
+package utils
+
+import "net/http"
+
+// ErrorCode documents one code that can appear in StandardizedErrorResponse.
+// Keep ErrorCatalogue in step with the codes passed to the New*Error
+// helpers; it is served at /error-codes for API consumers.
+type ErrorCode struct {
+	Code        string `json:"code"`
+	HTTPStatus  int    `json:"http_status"`
+	Description string `json:"description"`
+}
+
+var ErrorCatalogue = []ErrorCode{
+	// Request handling
+	{"ROUTE_NOT_FOUND", http.StatusNotFound, "No route matches the request path"},
+	{"METHOD_NOT_ALLOWED", http.StatusMethodNotAllowed, "The route exists but not for this method; see the Allow header"},
+	{"REQUEST_BODY_TOO_LARGE", http.StatusRequestEntityTooLarge, "The request body exceeds the size limit for the route"},
+	{"CORS_ORIGIN_NOT_ALLOWED", http.StatusForbidden, "A preflight request came from an origin that is not allowed"},
+	{"MISSING_API_KEY", http.StatusUnauthorized, "The X-Api-Key header is missing"},
+	{"INVALID_API_KEY", http.StatusForbidden, "The X-Api-Key header does not match"},
+	{"INVALID_METRICS_KEY", http.StatusUnauthorized, "The bearer token for /metrics is missing or wrong"},
+	{"INVALID_JSON", http.StatusBadRequest, "The request body is not valid JSON for this endpoint"},
+	{"INVALID_BODY", http.StatusBadRequest, "The request body could not be read"},
+	{"INVALID_QUERY", http.StatusBadRequest, "The query parameters could not be parsed"},
+	{"VALIDATION_ERROR", http.StatusBadRequest, "One or more fields failed validation; see errors"},
+	{"INVALID_IDEMPOTENCY_KEY", http.StatusBadRequest, "The Idempotency-Key header is longer than 255 characters"},
+	{"IDEMPOTENCY_KEY_REUSED", http.StatusConflict, "The Idempotency-Key was already used with a different request"},
+	{"IDEMPOTENCY_REQUEST_IN_PROGRESS", http.StatusConflict, "A request with the same Idempotency-Key is still running"},
+	{"REQUEST_CANCELED", StatusClientClosedRequest, "The client closed the request before it finished"},
+	{"QUERY_TIMEOUT", http.StatusGatewayTimeout, "A database query took longer than its timeout"},
+	{"DATABASE_UNAVAILABLE", http.StatusServiceUnavailable, "The database could not be reached; retry later"},
+	{"DATABASE_ERROR", http.StatusInternalServerError, "A database operation failed"},
+	{"ENCRYPTION_ERROR", http.StatusInternalServerError, "Personal data could not be encrypted or decrypted"},
+	{"INTERNAL_ERROR", http.StatusInternalServerError, "An unexpected error occurred"},
+
+	// Registrations
+	{"REGISTRATION_NOT_FOUND", http.StatusNotFound, "No registration has the given ID"},
+	{"DUPLICATE_EMAIL", http.StatusBadRequest, "The email is already registered or repeated in the batch"},
+	{"DUPLICATE_PHONE", http.StatusBadRequest, "The phone number is already registered or repeated in the batch"},
+	{"BATCH_VALIDATION_ERROR", http.StatusBadRequest, "At least one batch item was rejected; see errors"},
+	{"CSV_ERROR", http.StatusInternalServerError, "The CSV export could not be written"},
+	{"INVALID_STATS_FIELD", http.StatusInternalServerError, "The statistics query used an unsupported field"},
+	{"INVALID_STATS_PERIOD", http.StatusInternalServerError, "The statistics query used an unsupported period"},
+
+	// Imports
+	{"MISSING_FILE", http.StatusBadRequest, "No file was uploaded in the file field"},
+	{"IMPORT_FILE_TOO_LARGE", http.StatusBadRequest, "The uploaded file exceeds the import size limit"},
+	{"IMPORT_UNSUPPORTED_FORMAT", http.StatusBadRequest, "Only .csv and .xlsx files can be imported"},
+	{"IMPORT_INVALID_MAPPING", http.StatusBadRequest, "The mapping field is not a JSON object of header to field"},
+	{"IMPORT_READ_ERROR", http.StatusBadRequest, "The uploaded file could not be read"},
+	{"IMPORT_PARSE_ERROR", http.StatusBadRequest, "The uploaded file is not valid CSV or XLSX"},
+	{"IMPORT_EMPTY_FILE", http.StatusBadRequest, "The uploaded file has no header row"},
+	{"IMPORT_MISSING_COLUMNS", http.StatusBadRequest, "Required columns are missing from the header row"},
+	{"IMPORT_VALIDATION_ERROR", http.StatusBadRequest, "Rows were invalid and skip_invalid was not set; see errors"},
+
+	// Referral codes
+	{"REFERRAL_CODE_NOT_FOUND", http.StatusNotFound, "No referral code matches"},
+	{"INVALID_REFERRAL_CODE", http.StatusBadRequest, "The referral code does not exist"},
+	{"DUPLICATE_REFERRAL_CODE", http.StatusBadRequest, "A referral code with this value already exists"},
+
+	// Invite codes
+	{"INVITE_CODE_NOT_FOUND", http.StatusNotFound, "No invite code matches"},
+	{"INVITE_CODE_REQUIRED", http.StatusForbidden, "Registration is invite-only and no invite code was given"},
+	{"INVALID_INVITE_CODE", http.StatusBadRequest, "The invite code does not exist"},
+	{"INVITE_CODE_EXPIRED", http.StatusBadRequest, "The invite code has expired"},
+	{"INVITE_CODE_EXHAUSTED", http.StatusBadRequest, "The invite code has no uses left"},
+	{"INVITE_CODE_UNAVAILABLE", http.StatusBadRequest, "The invite code cannot cover the requested registrations"},
+	{"DUPLICATE_INVITE_CODE", http.StatusBadRequest, "An invite code with this value already exists"},
+
+	// Data subject requests
+	{"DATA_SUBJECT_NOT_FOUND", http.StatusNotFound, "No personal data was found for the email or phone"},
+}
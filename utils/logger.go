@@ -9,16 +9,22 @@ import (
 )
 
 func InitLogger() {
+	// Redaction runs on the JSON line, before the console writer adds
+	// colours.
 	if os.Getenv("APP_ENV") != "production" {
-		log.Logger = log.Output(zerolog.ConsoleWriter{
+		log.Logger = log.Output(redactingWriter{out: zerolog.ConsoleWriter{
 			Out:        os.Stdout,
 			TimeFormat: time.RFC3339,
-		})
+		}})
 	} else {
 		zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-		log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+		log.Logger = zerolog.New(redactingWriter{out: os.Stdout}).With().Timestamp().Logger()
 	}
 
+	// zerolog.Ctx falls back to the global logger for contexts that were
+	// not created by the access log middleware, e.g. background workers.
+	zerolog.DefaultContextLogger = &log.Logger
+
 	logLevel := os.Getenv("LOG_LEVEL")
 	switch logLevel {
 	case "debug":
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"io"
+	"regexp"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,})`)
+	// phonePattern matches 10-15 digit runs (optionally with +, spaces or
+	// dashes) that start a token. Runs directly after ':' or '-' are
+	// skipped so JSON numbers such as timestamps and UUID segments are
+	// left alone.
+	phonePattern = regexp.MustCompile(`(^|[^\w.:\-])(\+?\d[\d \-]{8,16}\d)`)
+	digitPattern = regexp.MustCompile(`\d`)
+)
+
+// RedactPII masks email addresses and phone numbers in s, keeping the
+// first character of an email's local part and its domain, and the last
+// two digits of a phone number.
+func RedactPII(s []byte) []byte {
+	s = emailPattern.ReplaceAll(s, []byte("${1}***@${2}"))
+	return phonePattern.ReplaceAllFunc(s, func(match []byte) []byte {
+		groups := phonePattern.FindSubmatch(match)
+		digits := digitPattern.FindAll(groups[2], -1)
+		if len(digits) < 10 {
+			return match
+		}
+		masked := append([]byte{}, groups[1]...)
+		masked = append(masked, "***"...)
+		masked = append(masked, digits[len(digits)-2][0], digits[len(digits)-1][0])
+		return masked
+	})
+}
+
+// redactingWriter applies RedactPII to every log line before it is
+// written, so personal data never reaches the log output whichever field
+// or error message carried it.
+type redactingWriter struct {
+	out io.Writer
+}
+
+func (w redactingWriter) Write(p []byte) (int, error) {
+	if _, err := w.out.Write(RedactPII(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
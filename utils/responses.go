@@ -1,12 +1,18 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// StatusClientClosedRequest is the non-standard status (used by nginx) for
+// requests the client abandoned before a response was written.
+const StatusClientClosedRequest = 499
+
 type StandardizedErrorResponse struct {
 	Status    string            `json:"status"`
 	Code      string            `json:"code"`
@@ -33,8 +39,22 @@ type AppError struct {
 	Message          string
 	Err              error
 	ValidationErrors []ValidationError
+	// Kind is the sentinel errors.Is matches, set by the constructors.
+	// It is independent of HTTPCode: a duplicate is reported as 400 but
+	// is still a conflict.
+	Kind error
 }
 
+// Error kinds for errors.Is, so callers can tell "no such row" from a
+// failing database without comparing codes or statuses. errors.Is also
+// checks the wrapped Err, so an error that translates a kind into another
+// one should not wrap it.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrUnavailable = errors.New("unavailable")
+)
+
 func SendCreatedResponse(ctx *gin.Context, message string, requestID string, data interface{}) {
 	sendSuccessResponse(ctx, http.StatusCreated, message, requestID, data)
 }
@@ -62,12 +82,21 @@ func (ae AppError) Error() string {
 	return ""
 }
 
+func (ae AppError) Unwrap() error {
+	return ae.Err
+}
+
+func (ae AppError) Is(target error) bool {
+	return ae.Kind != nil && target == ae.Kind
+}
+
 func NewNotFoundError(code string, message string, err error) *AppError {
 	return &AppError{
 		HTTPCode: http.StatusNotFound,
 		Code:     code,
 		Message:  message,
 		Err:      err,
+		Kind:     ErrNotFound,
 	}
 }
 
@@ -80,6 +109,18 @@ func NewBadRequestError(code string, message string, err error) *AppError {
 	}
 }
 
+// NewDuplicateError reports a value that already exists. It keeps the 400
+// status clients already handle but matches ErrConflict.
+func NewDuplicateError(code string, message string, err error) *AppError {
+	return &AppError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     code,
+		Message:  message,
+		Err:      err,
+		Kind:     ErrConflict,
+	}
+}
+
 func NewInternalServerError(code string, message string, err error) *AppError {
 	return &AppError{
 		HTTPCode: http.StatusInternalServerError,
@@ -107,16 +148,81 @@ func NewForbiddenError(code string, message string, err error) *AppError {
 	}
 }
 
+func NewConflictError(code string, message string, err error) *AppError {
+	return &AppError{
+		HTTPCode: http.StatusConflict,
+		Code:     code,
+		Message:  message,
+		Err:      err,
+		Kind:     ErrConflict,
+	}
+}
+
+func NewServiceUnavailableError(code string, message string, err error) *AppError {
+	return &AppError{
+		HTTPCode: http.StatusServiceUnavailable,
+		Code:     code,
+		Message:  message,
+		Err:      err,
+		Kind:     ErrUnavailable,
+	}
+}
+
+func NewGatewayTimeoutError(code string, message string, err error) *AppError {
+	return &AppError{
+		HTTPCode: http.StatusGatewayTimeout,
+		Code:     code,
+		Message:  message,
+		Err:      err,
+		Kind:     ErrUnavailable,
+	}
+}
+
+func NewMethodNotAllowedError(code string, message string, err error) *AppError {
+	return &AppError{
+		HTTPCode: http.StatusMethodNotAllowed,
+		Code:     code,
+		Message:  message,
+		Err:      err,
+	}
+}
+
+func NewPayloadTooLargeError(code string, message string, err error) *AppError {
+	return &AppError{
+		HTTPCode: http.StatusRequestEntityTooLarge,
+		Code:     code,
+		Message:  message,
+		Err:      err,
+	}
+}
+
+func NewClientClosedRequestError(code string, message string, err error) *AppError {
+	return &AppError{
+		HTTPCode: StatusClientClosedRequest,
+		Code:     code,
+		Message:  message,
+		Err:      err,
+	}
+}
+
 func HandleErrorResponse(ctx *gin.Context, err error, requestID string) {
 	var response StandardizedErrorResponse
 	response.RequestID = requestID
 	response.Status = "ERROR"
 
-	appErr, ok := err.(*AppError)
-	if !ok {
+	var appErr *AppError
+	if !errors.As(err, &appErr) {
 		appErr = NewInternalServerError("INTERNAL_ERROR", "An unexpected error occurred", err)
 	}
 
+	// Reading past the body limit surfaces wherever the body is read
+	// (binding, multipart parsing, idempotency hashing), so it is mapped
+	// here rather than at every call site.
+	var maxBytesErr *http.MaxBytesError
+	if appErr.HTTPCode < http.StatusInternalServerError && errors.As(appErr.Err, &maxBytesErr) {
+		appErr = NewPayloadTooLargeError("REQUEST_BODY_TOO_LARGE", fmt.Sprintf("Request bodies are limited to %d bytes", maxBytesErr.Limit), err)
+	}
+
 	response.Code = appErr.Code
 	response.Message = appErr.Message
 
@@ -127,10 +233,17 @@ func HandleErrorResponse(ctx *gin.Context, err error, requestID string) {
 	ctx.JSON(appErr.HTTPCode, response)
 }
 
+// RequestIDContextKey holds the ID chosen by RequestIDMiddleware.
+const RequestIDContextKey = "request_id"
+
+// GetRequestID returns the request's ID. It is normally the one stored by
+// RequestIDMiddleware; handlers reached without it get a fresh ID that is
+// stored so every later call agrees.
 func GetRequestID(ctx *gin.Context) string {
-	requestID := ctx.GetHeader("X-Request-ID")
-	if requestID == "" {
-		requestID = uuid.New().String()
+	if requestID := ctx.GetString(RequestIDContextKey); requestID != "" {
+		return requestID
 	}
+	requestID := uuid.New().String()
+	ctx.Set(RequestIDContextKey, requestID)
 	return requestID
 }
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAppErrorKinds(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *AppError
+		kind error
+	}{
+		{"not found", NewNotFoundError("X", "x", nil), ErrNotFound},
+		{"conflict", NewConflictError("X", "x", nil), ErrConflict},
+		{"duplicate", NewDuplicateError("DUPLICATE_EMAIL", "x", nil), ErrConflict},
+		{"unavailable", NewServiceUnavailableError("X", "x", nil), ErrUnavailable},
+		{"timeout", NewGatewayTimeoutError("X", "x", nil), ErrUnavailable},
+		{"bad request", NewBadRequestError("X", "x", nil), nil},
+		{"server error", NewInternalServerError("X", "x", nil), nil},
+	}
+
+	kinds := []error{ErrNotFound, ErrConflict, ErrUnavailable}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("context: %w", tt.err)
+			for _, kind := range kinds {
+				if got, want := errors.Is(wrapped, kind), kind == tt.kind; got != want {
+					t.Errorf("errors.Is(%v) = %v, want %v", kind, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDuplicateErrorKeepsItsStatus(t *testing.T) {
+	if err := NewDuplicateError("DUPLICATE_PHONE", "x", nil); err.HTTPCode != 400 {
+		t.Errorf("HTTPCode = %d, want 400", err.HTTPCode)
+	}
+}